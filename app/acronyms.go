@@ -0,0 +1,216 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"write-better/rules"
+)
+
+var (
+	// acronymPattern finds words of two or more capital letters along with
+	// any digits and a plural "s" such as "API", "HTTP2" or "URLs"
+	acronymPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9]*[A-Z][0-9]*)s?\b`)
+	// romanNumeral finds acronyms which are really Roman numerals up to 39.
+	// Larger ones are left alone since they look like acronyms such as "CLI".
+	romanNumeral = regexp.MustCompile(`^X{0,3}(IX|IV|V?I{0,3})$`)
+	// trailingDefinition finds a spelled out form in brackets right after an
+	// acronym as in "API (application programming interface)"
+	trailingDefinition = regexp.MustCompile(`^\s*\(\s*([^()]+?)\s*\)`)
+)
+
+// knownAcronyms are acronyms readers are expected to know without them being
+// spelled out
+var knownAcronyms = []string{"OK", "AM", "PM", "TV", "US", "USA", "UK", "EU", "UN", "ID", "FAQ", "CEO"}
+
+// acronymUse is a single use of an acronym in the document
+type acronymUse struct {
+	chunk   *rules.Chunk
+	indices []int
+	text    string
+	acronym string
+	defines bool
+}
+
+// AcronymProcessor processes the acronyms used throughout a document
+type AcronymProcessor struct {
+	// Message is the message given when an acronym is used before it is
+	// spelled out where %s is replaced with the acronym
+	Message string
+	// OnceMessage is the message given when an acronym is spelled out but
+	// never used again where %s is replaced with the acronym
+	OnceMessage string
+	// Known are the acronyms which don't need to be spelled out
+	Known []*rules.ListEntry
+}
+
+// UseAcronymProcessor is a convenience variable for referencing an AcronymProcessor
+var UseAcronymProcessor = AcronymProcessor{
+	Message:     "'%s' is used before it is spelled out. Spell it out the first time, such as \"Application Programming Interface (API)\".",
+	OnceMessage: "'%s' is spelled out but only used once. Use the spelled out form on its own instead.",
+	Known:       defaultKnownAcronyms(),
+}
+
+// defaultKnownAcronyms builds the phrase list entries for the known acronyms
+func defaultKnownAcronyms() []*rules.ListEntry {
+	var entries []*rules.ListEntry
+
+	for _, acronym := range knownAcronyms {
+		entries = append(entries, rules.NewListEntry(acronym, nil, ""))
+	}
+
+	return entries
+}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "acronym",
+		Name:        "Acronyms",
+		Legend:      "Undefined Acronyms",
+		Description: "Acronyms are a mystery to readers who don't already know them. Spell an acronym out the first time it is used, and skip the acronym altogether if it is only used once.",
+		Message:     UseAcronymProcessor.Message,
+		Messages:    map[string]string{"once": UseAcronymProcessor.OnceMessage},
+		Category:    "clarity",
+		Color:       "95, 95, 190",
+		NewDocument: func(r rules.Rule) rules.DocumentProcessor {
+			return AcronymProcessor{
+				Message:     r.Message,
+				OnceMessage: r.NamedMessage("once", UseAcronymProcessor.OnceMessage),
+				Known:       r.Entries(UseAcronymProcessor.Known),
+			}
+		},
+	})
+}
+
+// ProcessDocument handles the processing for acronym matches. Every use of an
+// acronym before it is spelled out is flagged, as is the place an acronym is
+// spelled out when it is never used again.
+func (p AcronymProcessor) ProcessDocument(chunks rules.Chunks) {
+	var uses []acronymUse
+
+	for _, c := range chunks {
+		uses = append(uses, p.findAcronyms(c)...)
+	}
+
+	defined := make(map[string]bool)
+	definedAt := make(map[string]acronymUse)
+	counts := make(map[string]int)
+
+	for _, use := range uses {
+		counts[use.acronym]++
+
+		if use.defines && !defined[use.acronym] {
+			defined[use.acronym] = true
+			definedAt[use.acronym] = use
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, use := range uses {
+		if defined[use.acronym] {
+			if use.defines {
+				seen[use.acronym] = true
+			}
+
+			if !seen[use.acronym] {
+				addAcronymMatch(use, p.Message)
+			}
+			continue
+		}
+
+		// Acronyms which are never spelled out are only flagged the first
+		// time to keep the noise down
+		if !seen[use.acronym] {
+			seen[use.acronym] = true
+			addAcronymMatch(use, p.Message)
+		}
+	}
+
+	for acronym, use := range definedAt {
+		if counts[acronym] == 1 {
+			addAcronymMatch(use, p.OnceMessage)
+		}
+	}
+}
+
+// findAcronyms finds the acronyms used in a chunk. Chunks written entirely in
+// capitals such as headings are skipped.
+func (p AcronymProcessor) findAcronyms(c *rules.Chunk) []acronymUse {
+	var uses []acronymUse
+
+	if strings.IndexFunc(c.Data, unicode.IsLower) < 0 {
+		return nil
+	}
+
+	for _, found := range acronymPattern.FindAllStringSubmatchIndex(c.Data, -1) {
+		acronym := c.Data[found[2]:found[3]]
+		if romanNumeral.MatchString(acronym) || p.isKnown(acronym) {
+			continue
+		}
+
+		uses = append(uses, acronymUse{
+			chunk:   c,
+			indices: RuneIndices(c.Data, found[:2]),
+			text:    c.Data[found[0]:found[1]],
+			acronym: acronym,
+			defines: isAcronymDefinition(c.Data, acronym, found[0], found[1]),
+		})
+	}
+
+	return uses
+}
+
+// isKnown checks if an acronym doesn't need to be spelled out
+func (p AcronymProcessor) isKnown(acronym string) bool {
+	for _, entry := range p.Known {
+		if strings.EqualFold(entry.Phrase, acronym) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAcronymDefinition checks if the acronym between the byte offsets start
+// and end is spelled out right there. That is either the spelled out form
+// followed by the acronym in brackets or the other way around. The spelled out
+// form needs a word starting with the same letter as the acronym.
+func isAcronymDefinition(data string, acronym string, start int, end int) bool {
+	// Application Programming Interface (API)
+	if start > 0 && end < len(data) && data[start-1] == '(' && data[end] == ')' {
+		words := Words(data[:start-1])
+		if len(words) > len(acronym)+2 {
+			words = words[len(words)-len(acronym)-2:]
+		}
+
+		return wordStartsWith(words, acronym[0])
+	}
+
+	// API (Application Programming Interface)
+	if m := trailingDefinition.FindStringSubmatch(data[end:]); m != nil {
+		words := Words(m[1])
+		return len(words) >= 2 && wordStartsWith(words, acronym[0])
+	}
+
+	return false
+}
+
+// wordStartsWith checks if any of the words starts with the letter ignoring
+// case
+func wordStartsWith(words []string, letter byte) bool {
+	for _, word := range words {
+		if strings.EqualFold(word[:1], string(letter)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addAcronymMatch adds a match for the use of an acronym to its chunk
+func addAcronymMatch(use acronymUse, msg string) {
+	c := use.chunk
+	c.Matches = append(c.Matches, rules.NewMatch(use.text, "acronym", use.indices, FormatMessage(msg, use.acronym)))
+	c.Score += 1
+}
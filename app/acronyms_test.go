@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestAcronymMessages(t *testing.T) {
+	rule := rules.Rule{
+		Message:  "Spell out %s first. 100% of readers thank you.",
+		Messages: map[string]string{"once": "Only one %s."},
+	}
+
+	info, _ := rules.LookupProcessor("acronym")
+	processor := info.NewDocument(rule)
+
+	chunks := rules.Chunks{
+		rules.NewChunk(0, "The API is new."),
+		rules.NewChunk(1, "A Content Delivery Network (CDN) helps."),
+	}
+	processor.ProcessDocument(chunks)
+
+	tests := []struct {
+		chunk *rules.Chunk
+		want  string
+	}{
+		{chunks[0], "Spell out API first. 100% of readers thank you."},
+		{chunks[1], "Only one CDN."},
+	}
+
+	for _, test := range tests {
+		if len(test.chunk.Matches) != 1 || test.chunk.Matches[0].Message != test.want {
+			t.Errorf("%q gave %v, want one match with %q", test.chunk.Data, test.chunk.Matches, test.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MaxRequestSize limits the size of a request body sent to the API
+const MaxRequestSize = 10 << 20
+
+// apiRequest is the JSON body accepted by the API
+type apiRequest struct {
+	// Text is the text to be analyzed
+	Text string `json:"text"`
+	// Format is the format of the text such as "text" or "markdown"
+	Format string `json:"format"`
+	// Profile is the name of the style profile to use
+	Profile string `json:"profile"`
+	// Labels are the processor labels to fix when fixing text
+	Labels []string `json:"labels"`
+}
+
+// apiAnalysis is the full analysis document returned by the API
+type apiAnalysis struct {
+	Score       int            `json:"score"`
+	Counts      map[string]int `json:"counts"`
+	Summary     Summary        `json:"summary"`
+	ReadTime    string         `json:"readTime"`
+	Readability Readability    `json:"readability"`
+	Frequency   WordFrequency  `json:"frequency"`
+	Sentences   SentenceStats  `json:"sentences"`
+	Matches     []MatchResult  `json:"matches"`
+}
+
+// apiFix is the fixed text document returned by the API
+type apiFix struct {
+	Text    string `json:"text"`
+	Diff    string `json:"diff"`
+	Applied []Edit `json:"applied"`
+}
+
+// apiAnalyzeHandler runs the submitted text through the processors and
+// returns the results as JSON. The body may either be raw text or a JSON
+// document with a "text" field. The format of the text and the style profile
+// can be picked with "format" and "profile" fields or query parameters.
+func apiAnalyzeHandler(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		writeAPIError(w, http.StatusMethodNotAllowed, "only POST is allowed")
+		return
+	}
+
+	data, err := readAPIRequest(w, req)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	profile, err := appConfig.Profile(data.Profile)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The text and format come from the request so errors are the client's
+	chunks, summary, err := Analyze(data.Text, data.Format, profile)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	score, counts := CountMatches(chunks)
+
+	writeJSON(w, http.StatusOK, apiAnalysis{
+		Score:       score,
+		Counts:      counts,
+		Summary:     summary,
+		ReadTime:    GetReadTime(summary["words"]),
+		Readability: GetReadability(chunks, summary),
+		Frequency:   GetWordFrequency(chunks, summary),
+		Sentences:   GetSentenceStats(chunks),
+		Matches:     FlattenMatches(data.Text, chunks),
+	})
+}
+
+// apiFixHandler applies the suggested replacements to the submitted text and
+// returns the fixed text along with a unified diff of the changes. Only the
+// labels given in a "labels" field or query parameter are fixed, otherwise
+// only the matches CollectEdits takes as safe are.
+func apiFixHandler(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		writeAPIError(w, http.StatusMethodNotAllowed, "only POST is allowed")
+		return
+	}
+
+	data, err := readAPIRequest(w, req)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	profile, err := appConfig.Profile(data.Profile)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	chunks, _, err := Analyze(data.Text, data.Format, profile)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fixed, applied := ApplyEdits(data.Text, CollectEdits(FlattenMatches(data.Text, chunks), data.Labels))
+
+	writeJSON(w, http.StatusOK, apiFix{
+		Text:    fixed,
+		Diff:    UnifiedDiff("a/text", "b/text", data.Text, applied),
+		Applied: charEdits(data.Text, applied),
+	})
+}
+
+// charEdits copies the edits with their byte offsets turned into character
+// offsets so they line up with the offsets of the matches. The edits need to
+// be sorted like the ones ApplyEdits gives back.
+func charEdits(text string, edits []Edit) []Edit {
+	chars := make([]Edit, len(edits))
+
+	lines := NewLineCounter(text)
+	for i, edit := range edits {
+		edit.Start = lines.Char(edit.Start)
+		edit.End = lines.Char(edit.End)
+		chars[i] = edit
+	}
+
+	return chars
+}
+
+// readAPIRequest pulls the text out of the request body based on its content
+// type along with the requested profile. Bodies over MaxRequestSize are
+// refused.
+func readAPIRequest(w http.ResponseWriter, req *http.Request) (apiRequest, error) {
+	var data apiRequest
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, MaxRequestSize))
+	if err != nil {
+		return data, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.Unmarshal(body, &data); err != nil {
+			return data, err
+		}
+	} else {
+		data.Text = string(body)
+	}
+
+	if data.Format == "" {
+		data.Format = req.URL.Query().Get("format")
+	}
+
+	if data.Profile == "" {
+		data.Profile = req.URL.Query().Get("profile")
+	}
+
+	if labels := req.URL.Query().Get("labels"); len(data.Labels) == 0 && labels != "" {
+		data.Labels = strings.Split(labels, ",")
+	}
+
+	return data, nil
+}
+
+// writeJSON encodes the data as the JSON response
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeAPIError sends an error message back as a JSON response
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// postAPI sends a JSON body to an API handler and decodes its response
+func postAPI(t *testing.T, handler http.HandlerFunc, body string, result interface{}) int {
+	req, err := http.NewRequest("POST", "/api/v1/", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if result != nil && w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return w.Code
+}
+
+func TestAPIAnalyzeCharacterOffsets(t *testing.T) {
+	text := "Café owners in Zürich utilize it."
+
+	var analysis apiAnalysis
+	if code := postAPI(t, apiAnalyzeHandler, `{"text": "`+text+`"}`, &analysis); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+
+	for _, m := range analysis.Matches {
+		if m.Match != "utilize" {
+			continue
+		}
+
+		if m.Start != 22 || m.End != 29 || m.Column != 23 {
+			t.Errorf("utilize is at %d-%d column %d, want 22-29 column 23", m.Start, m.End, m.Column)
+		}
+		if got := string([]rune(text)[m.Start:m.End]); got != m.Match {
+			t.Errorf("the offsets give %q, want %q", got, m.Match)
+		}
+
+		return
+	}
+
+	t.Errorf("no match for utilize in %v", analysis.Matches)
+}
+
+func TestAPIFixCharacterOffsets(t *testing.T) {
+	var fix apiFix
+	if code := postAPI(t, apiFixHandler, `{"text": "Café owners utilize it.", "labels": ["wordy"]}`, &fix); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+
+	if fix.Text != "Café owners use it." {
+		t.Errorf("fixed text is %q", fix.Text)
+	}
+	if len(fix.Applied) != 1 || fix.Applied[0].Start != 12 || fix.Applied[0].End != 19 {
+		t.Errorf("applied %v, want one edit at 12-19", fix.Applied)
+	}
+}
+
+func TestAPIBadRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown format", `{"text": "Some text.", "format": "pdf"}`},
+		{"malformed JSON", `{"text": "Some text.`},
+		{"unknown profile", `{"text": "Some text.", "profile": "missing"}`},
+		{"body too large", `{"text": "` + strings.Repeat("a", MaxRequestSize) + `"}`},
+	}
+
+	for _, test := range tests {
+		for _, handler := range []http.HandlerFunc{apiAnalyzeHandler, apiFixHandler} {
+			if code := postAPI(t, handler, test.body, nil); code != http.StatusBadRequest {
+				t.Errorf("%s gave status %d, want %d", test.name, code, http.StatusBadRequest)
+			}
+		}
+	}
+}
@@ -1,84 +1,88 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
+	"fmt"
+	"path/filepath"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"write-better/rules"
 )
 
-// Match represents an actual matched result after processing
-type Match struct {
-	// Match is the actual word / phrase that matches
-	Match string
-	// Label is the type of processor
-	Label string
-	// Indices are the start and end points of the match
-	Indices []int
-	// Message is the message from the processor
-	Message string
-}
+// Summary holds the overall counts gathered while chunking text
+type Summary map[string]int
 
-// NewMatch is a convenience function to build a new Match instance
-func NewMatch(match string, label string, indices []int, msg string) *Match {
-	return &Match{
-		Match:   match,
-		Label:   label,
-		Indices: indices,
-		Message: msg,
+// NewSummary is a convenience function to build an empty Summary instance
+func NewSummary() Summary {
+	return Summary{
+		"paragraphs": 0,
+		"sentences":  0,
+		"words":      0,
+		"characters": 0,
+		"letters":    0,
 	}
 }
 
-// Chunk is a piece of data created from the Chunker
-type Chunk struct {
-	// Index refers to the order of the Chunk
-	Index int
-	// Data is the text being stored
-	Data string
-	// FirstWord saves the first word of the text for analysis
-	FirstWord string
-	// IsNewParagraph marks when new paragraph delimiters are needed
-	IsNewParagraph bool
-	// Messages store the helpful messages returned from processors
-	Matches []*Match
-	// Score refers to the overall score of this Chunk
-	Score int
+// Chunker provides a skeleton for objects which can split data into smaller chunks of data
+type Chunker interface {
+	// Chunk takes in data and returns that data split into digestible
+	// poritions to use for parallel processing along with a Summary of the
+	// counts gathered along the way
+	Chunk() (rules.Chunks, Summary, error)
 }
 
-// NewChunk is a convenience function to build a new Chunk instance
-func NewChunk(idx int, data string) *Chunk {
-	var matches []*Match
-
-	return &Chunk{
-		Index:          idx,
-		Data:           data,
-		FirstWord:      "",
-		IsNewParagraph: false,
-		Matches:        matches,
-		Score:          0,
-	}
-}
+// Formats of text which can be chunked
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
 
-// Chunks is a simple way to reference data that has been split by a chunker
-type Chunks []*Chunk
+// NewChunker gives the Chunker for the format of the input with an empty
+// format meaning plain text. Sentences are split with the segmenter or the
+// default language's when it is nil.
+func NewChunker(format string, input string, segmenter *Segmenter) (Chunker, error) {
+	switch format {
+	case FormatText, "":
+		c := NewSentenceChunker(input)
+		c.Segmenter = segmenter
+		return c, nil
+	case FormatMarkdown:
+		c := NewMarkdownChunker(input)
+		c.Segmenter = segmenter
+		return c, nil
+	case FormatHTML:
+		c := NewHTMLChunker(input)
+		c.Segmenter = segmenter
+		return c, nil
+	}
 
-// ByChunk is a sorting mechanism for sorting a slice of chunks
-type ByChunk []*Chunk
+	return nil, fmt.Errorf("unknown format %q", format)
+}
 
-func (c ByChunk) Len() int           { return len(c) }
-func (c ByChunk) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
-func (c ByChunk) Less(i, j int) bool { return c[i].Index < c[j].Index }
+// DetectFormat guesses the format of a file from its name
+func DetectFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return FormatMarkdown
+	case ".html", ".htm", ".xhtml":
+		return FormatHTML
+	}
 
-// Chunker provides a skeleton for objects which can split data into smaller chunks of data
-type Chunker interface {
-	// Chunk takes in data and returns that data split into digestible
-	// poritions to use for parallel processing
-	Chunk() (Chunks, error)
+	return FormatText
 }
 
 // SentenceChunker is a Chunker instance which splits a string by sentences
 type SentenceChunker struct {
 	// Input is the text to be chunked
 	Input string
+	// Positions optionally maps each byte of Input to its offset in a source
+	// document that Input was extracted from
+	Positions []int
+	// Segmenter finds where sentences end, defaulting to the default
+	// language's when nil
+	Segmenter *Segmenter
 }
 
 // NewSentenceChunker is a convenience function to give us a SentenceChunker object
@@ -87,60 +91,74 @@ func NewSentenceChunker(input string) *SentenceChunker {
 }
 
 // SentenceChunker takes the passed in input and splits it by sentences
-func (c SentenceChunker) Chunk() (Chunks, error) {
-	var result Chunks
-	tmp := make(map[int]*Chunk)
-
-	appSummary = map[string]int{
-		"paragraphs": 0,
-		"sentences":  0,
-		"words":      0,
-		"characters": 0,
-		"letters":    0,
+func (c SentenceChunker) Chunk() (rules.Chunks, Summary, error) {
+	var result rules.Chunks
+	tmp := make(map[int]*rules.Chunk)
+	summary := NewSummary()
+
+	segmenter := c.Segmenter
+	if segmenter == nil {
+		segmenter = DefaultSegmenter()
 	}
 
-	s := bufio.NewScanner(bytes.NewBufferString(c.Input))
-
 	index := 0
+	offset := 0
 	firstWord := ""
-	for s.Scan() {
-		text := s.Text()
-		textLen := len(text)
+	for _, line := range strings.SplitAfter(c.Input, "\n") {
 		newPara := true
 
+		// Keep track of where the line starts so chunks know their position
+		// in the full text
+		lineOffset := offset + len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace))
+		offset += len(line)
+
 		// Clean surrounding whitespace
-		text = strings.TrimSpace(text)
+		text := strings.TrimSpace(line)
+		textLen := len(text)
+		ends := segmenter.Boundaries(text)
 
 		var prevRune rune
 		for i, r := range text {
 			// Check if we have a new sentence.
 			if tmp[index] == nil {
-				tmp[index] = NewChunk(index, "")
-				appSummary["sentences"] += 1
+				tmp[index] = rules.NewChunk(index, "")
+				tmp[index].Offset = c.position(lineOffset + i)
+				summary["sentences"] += 1
+				firstWord = ""
 
 				// In the case of a new paragraph we add one for the first
 				// word and increase the paragraph count
 				if newPara {
 					tmp[index].IsNewParagraph = true
-					appSummary["paragraphs"] += 1
-					appSummary["words"] += 1
+					summary["paragraphs"] += 1
+					summary["words"] += 1
 				}
 
 				newPara = false
 			}
 
+			// The character's bytes are added as they are so an invalid byte
+			// stays one byte wide rather than becoming the wider replacement
+			// character and offsets still line up with the input
+			_, size := utf8.DecodeRuneInString(text[i:])
+
 			// Add the current character to the chunk
-			tmp[index].Data += string(r)
+			tmp[index].Data += text[i : i+size]
+			if c.Positions != nil {
+				for k := 0; k < size; k++ {
+					tmp[index].Positions = append(tmp[index].Positions, c.position(lineOffset+i+k))
+				}
+			}
 
 			// Increase the number of characters and possibly letters
-			appSummary["characters"] += 1
+			summary["characters"] += 1
 			if IsAlpha(r) {
-				appSummary["letters"] += 1
+				summary["letters"] += 1
 			}
 
 			// Increase the word count
 			if IsSpace(r) && !IsSpace(prevRune) {
-				appSummary["words"] += 1
+				summary["words"] += 1
 			}
 
 			// Build the first word for easy reference later
@@ -156,10 +174,16 @@ func (c SentenceChunker) Chunk() (Chunks, error) {
 			// We move on if we're at the end of the sentence or in the case
 			// that a new paragraph does not have sentence terminators then we
 			// must increase as well to keep paragraphs correct.
-			if IsEndOfSentence(r) || textLen-1 == i {
+			end := i + size
+			lastRune := end == textLen
+			if ends[end] || lastRune {
+				// A sentence of one word has nothing after its first word
+				if tmp[index].FirstWord == "" {
+					tmp[index].FirstWord = firstWord
+				}
 				index++
-			} else if textLen-1 == i {
-				appSummary["words"] += 1
+			} else if lastRune {
+				summary["words"] += 1
 				index++
 			}
 
@@ -172,5 +196,14 @@ func (c SentenceChunker) Chunk() (Chunks, error) {
 		result = append(result, chunk)
 	}
 
-	return result, nil
+	return result, summary, nil
+}
+
+// position gets the source offset for an offset in the input
+func (c SentenceChunker) position(i int) int {
+	if c.Positions == nil {
+		return i
+	}
+
+	return c.Positions[i]
 }
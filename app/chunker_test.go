@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// chunkers builds each kind of chunker for the same input
+func chunkers(input string) map[string]Chunker {
+	return map[string]Chunker{
+		"text":     SentenceChunker{Input: input},
+		"markdown": MarkdownChunker{Input: input},
+		"html":     HTMLChunker{Input: input},
+	}
+}
+
+func TestChunkersMapEveryByte(t *testing.T) {
+	inputs := []string{
+		"Hello there \xff",
+		"Héllo thère. Ünïcödé 日本語 text 😀 here.",
+		"Bad \xfe\xfe bytes. And more \xff.",
+		"\xff",
+	}
+
+	for _, input := range inputs {
+		for name, chunker := range chunkers(input) {
+			chunks, _, err := chunker.Chunk()
+			if err != nil {
+				t.Errorf("%s: Chunk(%q) failed: %s", name, input, err)
+				continue
+			}
+
+			for _, chunk := range chunks {
+				if chunk.Positions != nil && len(chunk.Positions) != len(chunk.Data) {
+					t.Errorf("%s: Chunk(%q) has %d positions for %d bytes of %q", name, input, len(chunk.Positions), len(chunk.Data), chunk.Data)
+				}
+
+				for i := 0; i <= len(chunk.Data); i++ {
+					if offset := chunk.SourceOffset(i); offset < 0 || offset > len(input) {
+						t.Errorf("%s: Chunk(%q) maps byte %d of %q outside the input to %d", name, input, i, chunk.Data, offset)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestChunkerMatchSource(t *testing.T) {
+	input := "Ünïcödé \xff text is very good."
+
+	for name, chunker := range chunkers(input) {
+		chunks, _, err := chunker.Chunk()
+		if err != nil || len(chunks) != 1 {
+			t.Errorf("%s: Chunk(%q) gave %d chunks and %v", name, input, len(chunks), err)
+			continue
+		}
+
+		chunk := chunks[0]
+		start := strings.Index(chunk.Data, "very")
+		source := input[chunk.SourceOffset(start):chunk.SourceEnd(start+len("very"))]
+		if source != "very" {
+			t.Errorf("%s: match maps to %q in the source, want %q", name, source, "very")
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"write-better/rules"
+)
+
+// runCheck handles the `write-better check FILE...` command. Each file is run
+// through the processors and every match is printed as a diagnostic. When no
+// files are given the text is read from stdin. The returned value is the exit
+// code for the program.
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	threshold := flags.Int("threshold", 0, "highest score allowed for a file before failing")
+	configPath := flags.String("config", "", "path to a TOML file with style profiles")
+	profileName := flags.String("profile", "", "name of the style profile to use")
+	format := flags.String("format", "", "format of the text, either text, markdown or html (default is from the file extension)")
+	fix := flags.Bool("fix", false, "apply suggested replacements instead of printing diagnostics")
+	fixLabels := flags.String("fix-labels", "", "comma separated labels to fix (default is every grammar match with a single suggestion)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: write-better check [options] [FILE...]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	config := DefaultConfig()
+	if *configPath != "" {
+		var err error
+		if config, err = LoadConfig(*configPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	profile, err := config.Profile(*profileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var labels []string
+	if *fixLabels != "" {
+		labels = strings.Split(*fixLabels, ",")
+	}
+
+	status := 0
+	for _, file := range files {
+		if *fix {
+			if err := fixFile(file, fileFormat(file, *format), profile, labels); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", displayName(file), err)
+				return 2
+			}
+			continue
+		}
+
+		score, err := checkFile(file, fileFormat(file, *format), profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", displayName(file), err)
+			return 2
+		}
+
+		if score > *threshold {
+			fmt.Fprintf(os.Stderr, "%s: score %d exceeds threshold %d\n", displayName(file), score, *threshold)
+			status = 1
+		}
+	}
+
+	return status
+}
+
+// checkFile analyzes a single file, prints its diagnostics and returns the score
+func checkFile(file string, format string, profile Profile) (int, error) {
+	text, err := readInput(file)
+	if err != nil {
+		return 0, err
+	}
+
+	chunks, _, err := Analyze(text, format, profile)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, match := range FlattenMatches(text, chunks) {
+		msg := match.Message
+		if len(match.Suggestions) > 0 {
+			msg += " (try: " + rules.FormatSuggestions(match.Suggestions) + ")"
+		}
+
+		fmt.Printf("%s:%d:%d: %s: %s: %s\n", displayName(file), match.Line, match.Column, match.Severity, match.Label, msg)
+	}
+
+	score, _ := CountMatches(chunks)
+
+	return score, nil
+}
+
+// fixFile applies the suggested replacements to a file. Files are rewritten
+// in place with a diff of the changes printed, while stdin has its fixed text
+// printed instead.
+func fixFile(file string, format string, profile Profile, labels []string) error {
+	text, err := readInput(file)
+	if err != nil {
+		return err
+	}
+
+	if IsDocument(file) {
+		return fmt.Errorf("fixing documents is not supported")
+	}
+
+	chunks, _, err := Analyze(text, format, profile)
+	if err != nil {
+		return err
+	}
+
+	fixed, applied := ApplyEdits(text, CollectEdits(FlattenMatches(text, chunks), labels))
+
+	if file == "-" {
+		fmt.Print(fixed)
+		return nil
+	}
+
+	if fixed == text {
+		return nil
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(file, []byte(fixed), info.Mode()); err != nil {
+		return err
+	}
+
+	fmt.Print(UnifiedDiff("a/"+file, "b/"+file, text, applied))
+
+	return nil
+}
+
+// readInput reads the text from a file or stdin when the file is "-"
+func readInput(file string) (string, error) {
+	if file != "-" {
+		return readDocument(file)
+	}
+
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// fileFormat picks the format for a file using the requested format when given
+func fileFormat(file string, format string) string {
+	if format != "" || file == "-" {
+		return format
+	}
+
+	return DetectFormat(file)
+}
+
+// displayName gives the name of the file to use in diagnostics
+func displayName(file string) string {
+	if file == "-" {
+		return "<stdin>"
+	}
+
+	return file
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// captureCheck runs the check command and collects what it prints
+func captureCheck(t *testing.T, args ...string) (int, string, string) {
+	stdout, stderr := os.Stdout, os.Stderr
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	outRead, outWrite, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errRead, errWrite, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = outWrite, errWrite
+
+	var out, errOut bytes.Buffer
+	done := make(chan bool)
+	go func() {
+		io.Copy(&out, outRead)
+		done <- true
+	}()
+	go func() {
+		io.Copy(&errOut, errRead)
+		done <- true
+	}()
+
+	code := runCheck(args)
+
+	outWrite.Close()
+	errWrite.Close()
+	<-done
+	<-done
+
+	return code, out.String(), errOut.String()
+}
+
+func TestRunCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "notes.txt")
+	if err := ioutil.WriteFile(file, []byte("Fine.\nI could of gone.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wantLine := file + ":2:9: warning: grammar: Use 'could have' or 'could've'. 'Of' isn't a verb. (try: 'have')\n"
+
+	code, out, errOut := captureCheck(t, "-format", "text", file)
+	if code != 1 || out != wantLine {
+		t.Errorf("check gave %d with\n%s\nwant 1 with\n%s", code, out, wantLine)
+	}
+	if want := file + ": score 1 exceeds threshold 0\n"; errOut != want {
+		t.Errorf("check printed %q to stderr, want %q", errOut, want)
+	}
+
+	if code, out, _ := captureCheck(t, "-threshold", "1", file); code != 0 || out != wantLine {
+		t.Errorf("check under the threshold gave %d with %q, want 0", code, out)
+	}
+
+	if code, _, _ := captureCheck(t, filepath.Join(dir, "missing.txt")); code != 2 {
+		t.Errorf("check of a missing file gave %d, want 2", code)
+	}
+}
+
+func TestRunCheckFix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "notes.txt")
+	if err := ioutil.WriteFile(file, []byte("I could of gone.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code, _, _ := captureCheck(t, "-fix", file); code != 0 {
+		t.Errorf("check -fix gave %d, want 0", code)
+	}
+
+	fixed, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(fixed) != "I could have gone.\n" {
+		t.Errorf("fixed file is %q", fixed)
+	}
+}
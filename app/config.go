@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"write-better/rules"
+)
+
+// DefaultProfile is the name of the profile used when none is requested
+const DefaultProfile = "default"
+
+// Severities are the valid values for a rule's severity
+var Severities = []string{"error", "warning", "info"}
+
+// Profile is a named style profile made up of rules for each processor label
+type Profile struct {
+	// Name is the key the profile was loaded with
+	Name string `toml:"-"`
+	// Language picks the rules used to split sentences, defaulting to
+	// DefaultLanguage
+	Language string `toml:"language"`
+	// Rules are the processor settings keyed by label
+	Rules map[string]rules.Rule `toml:"rules"`
+}
+
+// Rule gets the settings for a processor label
+func (p Profile) Rule(label string) rules.Rule {
+	return p.Rules[label]
+}
+
+// Severity gets the severity for a processor label
+func (p Profile) Severity(label string) string {
+	if severity := p.Rule(label).Severity; severity != "" {
+		return severity
+	}
+
+	return rules.DefaultSeverity
+}
+
+// processorRule gets the settings for a processor with the processor's
+// default message filled in when the profile doesn't set one
+func (p Profile) processorRule(info *rules.ProcessorInfo) rules.Rule {
+	rule := p.Rule(info.Label)
+
+	rule.MessageSet = rule.Message != ""
+	if !rule.MessageSet {
+		rule.Message = info.Message
+	}
+
+	return rule
+}
+
+// Processors builds the pipeline of enabled processors for the profile
+func (p Profile) Processors() Pipeline {
+	var pipeline Pipeline
+
+	for _, info := range rules.RegisteredProcessors() {
+		rule := p.processorRule(info)
+		if !rule.IsEnabled() {
+			continue
+		}
+
+		switch {
+		case info.New != nil:
+			pipeline.Sentence = append(pipeline.Sentence, info.New(rule))
+		case info.NewParagraph != nil:
+			pipeline.Paragraph = append(pipeline.Paragraph, info.NewParagraph(rule))
+		case info.NewDocument != nil:
+			pipeline.Document = append(pipeline.Document, info.NewDocument(rule))
+		}
+	}
+
+	return pipeline
+}
+
+// Config holds all of the style profiles available to the application
+type Config struct {
+	// Profiles are the style profiles keyed by name
+	Profiles map[string]Profile `toml:"profiles"`
+	// Lists are user supplied phrase lists keyed by the label they report under
+	Lists map[string]ListConfig `toml:"lists"`
+	// Languages add to or define the languages used to split sentences
+	Languages map[string]LanguageConfig `toml:"languages"`
+	// Spelling picks the dictionary and custom word lists used to check
+	// spelling
+	Spelling SpellingConfig `toml:"spelling"`
+}
+
+// LanguageConfig holds the settings for splitting sentences in a language
+type LanguageConfig struct {
+	// Abbreviations are words ending in a full stop which don't end a
+	// sentence such as "approx." or "e.g."
+	Abbreviations []string `toml:"abbreviations"`
+}
+
+// DefaultConfig is a convenience function to build a Config with only the
+// default profile which runs every processor.
+func DefaultConfig() *Config {
+	return &Config{
+		Profiles: map[string]Profile{
+			DefaultProfile: Profile{Name: DefaultProfile},
+		},
+	}
+}
+
+// LoadConfig reads a TOML config file and checks that it is valid
+func LoadConfig(path string) (*Config, error) {
+	var config Config
+
+	if _, err := toml.DecodeFile(path, &config); err != nil {
+		return nil, err
+	}
+
+	// Everything is loaded and checked before any of it is registered so a
+	// config with a mistake doesn't leave the processors half changed
+	var lists []rules.ProcessorInfo
+	for _, label := range sortedListLabels(config.Lists) {
+		info, err := LoadList(label, config.Lists[label], filepath.Dir(path))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		lists = append(lists, info)
+	}
+
+	dictionary, err := LoadSpellingConfig(config.Spelling, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: spelling: %s", path, err)
+	}
+
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]Profile)
+	}
+
+	if _, ok := config.Profiles[DefaultProfile]; !ok {
+		config.Profiles[DefaultProfile] = Profile{}
+	}
+
+	for name, profile := range config.Profiles {
+		profile.Name = name
+		config.Profiles[name] = profile
+
+		if err := profile.validate(&config, lists); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %s", path, name, err)
+		}
+	}
+
+	for _, info := range lists {
+		rules.RegisterProcessor(info)
+	}
+
+	for language, lang := range config.Languages {
+		RegisterLanguage(language, lang.Abbreviations)
+	}
+
+	if dictionary != nil {
+		SetSpellingDictionary(dictionary)
+	}
+
+	return &config, nil
+}
+
+// sortedListLabels gives the labels of the phrase lists in order so they are
+// always registered in the same order
+func sortedListLabels(lists map[string]ListConfig) []string {
+	var labels []string
+	for label := range lists {
+		labels = append(labels, label)
+	}
+
+	sort.Strings(labels)
+
+	return labels
+}
+
+// validate makes sure a profile only refers to known labels, severities,
+// thresholds, messages and languages and that its phrases can be parsed. The
+// phrase lists and languages of the config being loaded count as known.
+func (p Profile) validate(config *Config, lists []rules.ProcessorInfo) error {
+	if _, ok := config.Languages[p.Language]; !ok {
+		if _, err := LookupSegmenter(p.Language); err != nil {
+			return err
+		}
+	}
+
+	for label, rule := range p.Rules {
+		info, ok := lookupProcessor(label, lists)
+		if !ok {
+			return fmt.Errorf("unknown rule %q", label)
+		}
+
+		for name, value := range rule.Thresholds {
+			min, ok := info.Thresholds[name]
+			if !ok {
+				return fmt.Errorf("rule %q: unknown threshold %q", label, name)
+			}
+
+			if value < min {
+				return fmt.Errorf("rule %q: threshold %q must be at least %d", label, name, min)
+			}
+		}
+
+		if info.Validate != nil {
+			if err := info.Validate(rule); err != nil {
+				return fmt.Errorf("rule %q: %s", label, err)
+			}
+		}
+
+		for name := range rule.Messages {
+			if _, ok := info.Messages[name]; !ok {
+				return fmt.Errorf("rule %q: unknown message %q", label, name)
+			}
+		}
+
+		if rule.Severity != "" && !isSeverity(rule.Severity) {
+			return fmt.Errorf("rule %q: unknown severity %q", label, rule.Severity)
+		}
+
+		if _, err := rules.ParsePhrases(append(rule.Phrases, rule.AddPhrases...)); err != nil {
+			return fmt.Errorf("rule %q: %s", label, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupProcessor finds a processor by its label among the registered ones
+// and the phrase lists waiting to be registered
+func lookupProcessor(label string, lists []rules.ProcessorInfo) (*rules.ProcessorInfo, bool) {
+	for i := range lists {
+		if lists[i].Label == label {
+			return &lists[i], true
+		}
+	}
+
+	return rules.LookupProcessor(label)
+}
+
+// Profile gets a profile by name with an empty name meaning the default
+func (c *Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+
+	return profile, nil
+}
+
+// ProfileNames lists the names of the profiles in order
+func (c *Config) ProfileNames() []string {
+	var names []string
+
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// isSeverity checks if the value is a valid severity
+func isSeverity(severity string) bool {
+	for _, s := range Severities {
+		if s == severity {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"write-better/rules"
+)
+
+// loadTestConfig writes the TOML to a temporary file and loads it
+func loadTestConfig(t *testing.T, data string) (*Config, error) {
+	dir, err := ioutil.TempDir("", "write-better")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return LoadConfig(path)
+}
+
+func TestLoadConfigThresholds(t *testing.T) {
+	tests := []struct {
+		rule string
+		err  string
+	}{
+		{"[profiles.default.rules.opener]\nthresholds = { run = 3 }", ""},
+		{"[profiles.default.rules.length]\nthresholds = { long = 100, very_long = 140 }", ""},
+		{"[profiles.default.rules.length]\nthresholds = { long = 100, very_long = 100 }", ""},
+		{"[profiles.default.rules.length]\nthresholds = { long = 100, very_long = 90 }", `threshold "very_long" (90) must be at least "long" (100)`},
+		{"[profiles.default.rules.length]\nthresholds = { very_long = 120 }", `threshold "very_long" (120) must be at least "long" (130)`},
+		{"[profiles.default.rules.opener]\nthresholds = { run = 1 }", `threshold "run" must be at least 2`},
+		{"[profiles.default.rules.opener]\nthresholds = { run = 0 }", `threshold "run" must be at least 2`},
+		{"[profiles.default.rules.paragraph]\nthresholds = { words = -5 }", `threshold "words" must be at least 1`},
+		{"[profiles.default.rules.length]\nthresholds = { longest = 100 }", `unknown threshold "longest"`},
+		{"[profiles.default.rules.adverb]\nthresholds = { run = 3 }", `unknown threshold "run"`},
+	}
+
+	for _, test := range tests {
+		_, err := loadTestConfig(t, test.rule)
+
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("LoadConfig(%q) failed: %s", test.rule, err)
+		case test.err != "" && err == nil:
+			t.Errorf("LoadConfig(%q) loaded, want an error with %q", test.rule, test.err)
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("LoadConfig(%q) failed with %q, want %q", test.rule, err, test.err)
+		}
+	}
+}
+
+func TestLoadConfigChangesNothingOnError(t *testing.T) {
+	list, err := ioutil.TempFile("", "phrases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(list.Name())
+
+	list.WriteString("synergy | cooperation\n")
+	list.Close()
+
+	// Processors and languages stay registered so each run needs its own
+	// label
+	label := filepath.Base(list.Name())
+
+	config := `
+[lists.` + label + `]
+files = ["` + filepath.ToSlash(list.Name()) + `"]
+
+[languages.` + label + `]
+abbreviations = ["abbr"]
+
+[profiles.default]
+language = "` + label + `"
+
+[profiles.default.rules.` + label + `]
+severity = "loud"
+`
+
+	if _, err := loadTestConfig(t, config); err == nil || !strings.Contains(err.Error(), `unknown severity "loud"`) {
+		t.Fatalf("LoadConfig with a bad severity gave %v", err)
+	}
+
+	if _, ok := rules.LookupProcessor(label); ok {
+		t.Error("the list was registered from a config with a mistake")
+	}
+	if _, err := LookupSegmenter(label); err == nil {
+		t.Error("the language was registered from a config with a mistake")
+	}
+
+	// The same config loads once the mistake is fixed
+	if _, err := loadTestConfig(t, strings.Replace(config, "loud", "info", 1)); err != nil {
+		t.Fatalf("LoadConfig gave %v", err)
+	}
+	if _, ok := rules.LookupProcessor(label); !ok {
+		t.Error("the list wasn't registered")
+	}
+}
+
+func TestLoadConfigMessages(t *testing.T) {
+	if _, err := loadTestConfig(t, "[profiles.default.rules.acronym]\nmessages = { once = \"Once.\" }"); err != nil {
+		t.Errorf("LoadConfig with a known message failed: %s", err)
+	}
+
+	_, err := loadTestConfig(t, "[profiles.default.rules.acronym]\nmessages = { twice = \"Twice.\" }")
+	if err == nil || !strings.Contains(err.Error(), `unknown message "twice"`) {
+		t.Errorf("LoadConfig with an unknown message gave %v", err)
+	}
+}
+
+// profileProcessor builds a processor the way a profile with the given
+// message for it would
+func profileProcessor(t *testing.T, label string, message string) rules.Processor {
+	info, ok := rules.LookupProcessor(label)
+	if !ok {
+		t.Fatalf("no processor %q", label)
+	}
+
+	profile := Profile{Rules: map[string]rules.Rule{label: {Message: message}}}
+
+	return info.New(profile.processorRule(info))
+}
+
+func TestProcessorRuleMessage(t *testing.T) {
+	info, _ := rules.LookupProcessor("hedge")
+
+	rule := Profile{}.processorRule(info)
+	if rule.Message != info.Message || rule.MessageSet {
+		t.Errorf("an unset message gave %q set %v, want the default unset", rule.Message, rule.MessageSet)
+	}
+
+	// A profile repeating the default message still replaces the messages
+	// of each phrase
+	c := profileProcessor(t, "hedge", info.Message).Process(rules.NewChunk(0, "I think so."))
+	if want := "'I think' weakens the statement."; len(c.Matches) != 1 || c.Matches[0].Message != want {
+		t.Errorf("the default message set in the profile gave %v, want %q", c.Matches, want)
+	}
+}
@@ -0,0 +1,641 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxEditDistance is the most edits a word can be from a misspelling to be
+// suggested for it
+const MaxEditDistance = 2
+
+// Dictionary is a set of correctly spelled words. Words are remembered in the
+// order they were added which is used to rank suggestions, so word lists
+// should put the most common words first.
+type Dictionary struct {
+	// words maps each word to the order it was added
+	words map[string]int
+	// byLength groups the words by their length in runes for suggestions
+	byLength map[int][]dictionaryWord
+}
+
+// dictionaryWord is a word kept for suggestions along with its lowercase
+// letters and the set of letters it uses
+type dictionaryWord struct {
+	word    string
+	lower   []rune
+	letters uint64
+}
+
+// NewDictionary is a convenience function to build an empty Dictionary
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		words:    make(map[string]int),
+		byLength: make(map[int][]dictionaryWord),
+	}
+}
+
+// LoadDictionary reads each of the dictionary files into a single Dictionary.
+// Files ending in .dic are Hunspell dictionaries with their .aff file next to
+// them and anything else is a plain word list.
+func LoadDictionary(paths ...string) (*Dictionary, error) {
+	d := NewDictionary()
+
+	for _, path := range paths {
+		var err error
+
+		if filepath.Ext(path) == ".dic" {
+			err = d.LoadHunspell(path)
+		} else {
+			err = d.LoadWordList(path)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// Add adds words to the dictionary
+func (d *Dictionary) Add(words ...string) {
+	for _, word := range words {
+		if _, ok := d.words[word]; ok || word == "" {
+			continue
+		}
+
+		d.words[word] = len(d.words)
+
+		lower := []rune(strings.ToLower(word))
+		d.byLength[len(lower)] = append(d.byLength[len(lower)], dictionaryWord{
+			word:    word,
+			lower:   lower,
+			letters: letterSet(lower),
+		})
+	}
+}
+
+// Len is the number of words in the dictionary
+func (d *Dictionary) Len() int {
+	return len(d.words)
+}
+
+// Contains checks if a word is spelled correctly. Lowercase words in the
+// dictionary also match when they are capitalized or written in capitals but
+// words with capitals such as names must keep them.
+func (d *Dictionary) Contains(word string) bool {
+	if _, ok := d.words[word]; ok {
+		return true
+	}
+
+	lower := strings.ToLower(word)
+	if word != lower && (word == strings.ToUpper(word) || word == titleCase(lower)) {
+		if _, ok := d.words[lower]; ok {
+			return true
+		}
+	}
+
+	if word == strings.ToUpper(word) {
+		_, ok := d.words[titleCase(lower)]
+		return ok
+	}
+
+	return false
+}
+
+// Suggest gives up to n words from the dictionary within MaxEditDistance of
+// a misspelled word
+func (d *Dictionary) Suggest(word string, n int) []string {
+	return Suggest(word, n, d)
+}
+
+// Suggest gives up to n words from any of the dictionaries within
+// MaxEditDistance of a misspelled word. The closest words come first and
+// words with the same first letter or added to a dictionary earlier win ties.
+func Suggest(word string, n int, dictionaries ...*Dictionary) []string {
+	var candidates []suggestion
+
+	target := []rune(strings.ToLower(word))
+	if len(target) == 0 {
+		return nil
+	}
+	letters := letterSet(target)
+
+	for _, d := range dictionaries {
+		for length := len(target) - MaxEditDistance; length <= len(target)+MaxEditDistance; length++ {
+			for _, w := range d.byLength[length] {
+				// Each edit adds or removes at most one letter from the set
+				// so most words are ruled out without working out the
+				// distance
+				if countBits(letters&^w.letters) > MaxEditDistance || countBits(w.letters&^letters) > MaxEditDistance {
+					continue
+				}
+
+				distance := EditDistance(target, w.lower, MaxEditDistance)
+				if distance > MaxEditDistance {
+					continue
+				}
+
+				candidates = append(candidates, suggestion{
+					word:        w.word,
+					distance:    distance,
+					sameInitial: w.lower[0] == target[0],
+					rank:        d.words[w.word],
+				})
+			}
+		}
+	}
+
+	sort.Sort(BySuggestion(candidates))
+
+	var result []string
+	for _, c := range candidates {
+		if len(result) == n {
+			break
+		}
+
+		if !containsString(result, c.word) {
+			result = append(result, c.word)
+		}
+	}
+
+	return result
+}
+
+// suggestion is a possible correction for a misspelled word
+type suggestion struct {
+	word        string
+	distance    int
+	sameInitial bool
+	rank        int
+}
+
+// BySuggestion is a sorting mechanism for sorting suggestions from best to
+// worst
+type BySuggestion []suggestion
+
+func (s BySuggestion) Len() int      { return len(s) }
+func (s BySuggestion) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s BySuggestion) Less(i, j int) bool {
+	if s[i].distance != s[j].distance {
+		return s[i].distance < s[j].distance
+	}
+
+	if s[i].sameInitial != s[j].sameInitial {
+		return s[i].sameInitial
+	}
+
+	return s[i].rank < s[j].rank
+}
+
+// EditDistance counts the insertions, deletions, substitutions and swaps of
+// neighbouring letters needed to turn a into b. Counting stops once every
+// path is over max, giving max+1.
+func EditDistance(a []rune, b []rune, max int) int {
+	if diff := len(a) - len(b); diff > max || -diff > max {
+		return max + 1
+	}
+
+	// Three rows are enough since a swap only looks back two rows
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	row := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		row[0] = i
+		best := row[0]
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			row[j] = minInt(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				row[j] = minInt(row[j], prev2[j-2]+1)
+			}
+
+			if row[j] < best {
+				best = row[j]
+			}
+		}
+
+		if best > max {
+			return max + 1
+		}
+
+		prev2, prev, row = prev, row, prev2
+	}
+
+	if prev[len(b)] > max {
+		return max + 1
+	}
+
+	return prev[len(b)]
+}
+
+// letterSet gives a bit for each letter in a word. Letters which end up
+// sharing a bit only let more words through.
+func letterSet(word []rune) uint64 {
+	var set uint64
+
+	for _, r := range word {
+		set |= 1 << (uint(r) % 64)
+	}
+
+	return set
+}
+
+// countBits counts the bits which are set
+func countBits(set uint64) int {
+	count := 0
+
+	for ; set != 0; set &= set - 1 {
+		count++
+	}
+
+	return count
+}
+
+// minInt gives the smallest of the numbers
+func minInt(first int, rest ...int) int {
+	for _, n := range rest {
+		if n < first {
+			first = n
+		}
+	}
+
+	return first
+}
+
+// titleCase capitalizes the first letter of a word
+func titleCase(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// LoadWordList adds the words from a plain word list with one word per line.
+// Blank lines and lines starting with # are ignored.
+func (d *Dictionary) LoadWordList(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		d.Add(strings.Fields(line)[0])
+	}
+
+	return s.Err()
+}
+
+// LoadHunspell adds the words from a Hunspell dictionary. The affix file is
+// found by swapping the .dic extension for .aff and is used to add every
+// prefixed and suffixed form of each word. Only UTF-8 dictionaries are read,
+// and compounding and affixes on affixes aren't supported.
+func (d *Dictionary) LoadHunspell(path string) error {
+	aff, err := loadAffixes(strings.TrimSuffix(path, ".dic") + ".aff")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+
+		// The first line is the number of words
+		if text == "" || strings.HasPrefix(text, "#") || line == 1 && isNumber(text) {
+			continue
+		}
+
+		word, flags := text, ""
+		if fields := strings.Fields(text); len(fields) > 0 {
+			word = fields[0]
+		}
+		if i := strings.Index(word, "/"); i >= 0 {
+			word, flags = word[:i], word[i+1:]
+		}
+
+		parsed, err := aff.parseFlags(flags)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s", path, line, err)
+		}
+
+		d.Add(aff.expand(word, parsed)...)
+	}
+
+	return s.Err()
+}
+
+// affixRule is a single way a prefix or suffix changes a word
+type affixRule struct {
+	strip     string
+	add       string
+	condition []affixCondition
+}
+
+// affixCondition matches a single letter in a rule's condition
+type affixCondition struct {
+	letters string
+	negate  bool
+	any     bool
+}
+
+// affixClass is a group of affix rules sharing a flag
+type affixClass struct {
+	prefix bool
+	cross  bool
+	rules  []affixRule
+}
+
+// affixFile holds the affix rules from a Hunspell .aff file
+type affixFile struct {
+	flagType string
+	aliases  []string
+	classes  map[string]*affixClass
+}
+
+// loadAffixes reads the parts of a Hunspell .aff file needed to build each
+// form of a word
+func loadAffixes(path string) (*affixFile, error) {
+	aff := &affixFile{classes: make(map[string]*affixClass)}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var err error
+
+		switch fields[0] {
+		case "SET":
+			if len(fields) > 1 && !strings.EqualFold(fields[1], "UTF-8") {
+				err = fmt.Errorf("unsupported encoding %q", fields[1])
+			}
+		case "FLAG":
+			if len(fields) > 1 {
+				aff.flagType = fields[1]
+			}
+		case "AF":
+			// The first AF line is the number of aliases
+			if len(fields) > 1 && !(len(aff.aliases) == 0 && isNumber(fields[1]) && len(fields) == 2) {
+				aff.aliases = append(aff.aliases, fields[1])
+			}
+		case "PFX", "SFX":
+			err = aff.parseAffix(fields)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, line, err)
+		}
+	}
+
+	return aff, s.Err()
+}
+
+// parseAffix reads either the header line of an affix class such as
+// "SFX D Y 4" or one of its rules such as "SFX D y ied [^aeiou]y"
+func (a *affixFile) parseAffix(fields []string) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("%s needs at least 4 fields", fields[0])
+	}
+
+	flag := fields[1]
+	class, ok := a.classes[flag]
+
+	if !ok {
+		a.classes[flag] = &affixClass{prefix: fields[0] == "PFX", cross: fields[2] == "Y"}
+		return nil
+	}
+
+	rule := affixRule{strip: fields[2], add: fields[3]}
+	if rule.strip == "0" {
+		rule.strip = ""
+	}
+
+	// Flags on the affix itself would add more affixes which isn't supported
+	if i := strings.Index(rule.add, "/"); i >= 0 {
+		rule.add = rule.add[:i]
+	}
+	if rule.add == "0" {
+		rule.add = ""
+	}
+
+	condition := "."
+	if len(fields) > 4 {
+		condition = fields[4]
+	}
+
+	var err error
+	if rule.condition, err = parseCondition(condition); err != nil {
+		return err
+	}
+
+	class.rules = append(class.rules, rule)
+
+	return nil
+}
+
+// parseCondition reads a rule's condition such as "[^aeiou]y" into the letters
+// it matches
+func parseCondition(condition string) ([]affixCondition, error) {
+	var result []affixCondition
+
+	if condition == "." {
+		return nil, nil
+	}
+
+	runes := []rune(condition)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			result = append(result, affixCondition{any: true})
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unclosed [ in condition %q", condition)
+			}
+
+			c := affixCondition{letters: string(runes[i+1 : end])}
+			if strings.HasPrefix(c.letters, "^") {
+				c.negate, c.letters = true, c.letters[1:]
+			}
+
+			result = append(result, c)
+			i = end
+		default:
+			result = append(result, affixCondition{letters: string(runes[i])})
+		}
+	}
+
+	return result, nil
+}
+
+// parseFlags splits the flags of a dictionary word using the flag type from
+// the affix file
+func (a *affixFile) parseFlags(flags string) ([]string, error) {
+	if flags == "" {
+		return nil, nil
+	}
+
+	if len(a.aliases) > 0 && isNumber(flags) {
+		n, _ := strconv.Atoi(flags)
+		if n < 1 || n > len(a.aliases) {
+			return nil, fmt.Errorf("unknown flag alias %s", flags)
+		}
+		flags = a.aliases[n-1]
+	}
+
+	var result []string
+
+	switch a.flagType {
+	case "long":
+		runes := []rune(flags)
+		for i := 0; i+1 < len(runes); i += 2 {
+			result = append(result, string(runes[i:i+2]))
+		}
+	case "num":
+		result = strings.Split(flags, ",")
+	default:
+		for _, r := range flags {
+			result = append(result, string(r))
+		}
+	}
+
+	return result, nil
+}
+
+// expand builds every form of a word from its flags
+func (a *affixFile) expand(word string, flags []string) []string {
+	forms := []string{word}
+
+	var prefixes, suffixes []*affixClass
+	for _, flag := range flags {
+		if class, ok := a.classes[flag]; ok {
+			if class.prefix {
+				prefixes = append(prefixes, class)
+			} else {
+				suffixes = append(suffixes, class)
+			}
+		}
+	}
+
+	for _, sfx := range suffixes {
+		for _, suffixed := range sfx.apply(word) {
+			forms = append(forms, suffixed)
+
+			if !sfx.cross {
+				continue
+			}
+
+			for _, pfx := range prefixes {
+				if pfx.cross {
+					forms = append(forms, pfx.apply(suffixed)...)
+				}
+			}
+		}
+	}
+
+	for _, pfx := range prefixes {
+		forms = append(forms, pfx.apply(word)...)
+	}
+
+	return forms
+}
+
+// apply gives the forms of a word made by each rule in the class which
+// matches it
+func (c *affixClass) apply(word string) []string {
+	var forms []string
+
+	runes := []rune(word)
+	for _, rule := range c.rules {
+		if !rule.matches(runes, c.prefix) {
+			continue
+		}
+
+		if c.prefix {
+			forms = append(forms, rule.add+strings.TrimPrefix(word, rule.strip))
+		} else {
+			forms = append(forms, strings.TrimSuffix(word, rule.strip)+rule.add)
+		}
+	}
+
+	return forms
+}
+
+// matches checks if a rule applies to a word. Prefix conditions match the
+// start of the word and suffix conditions match the end.
+func (r affixRule) matches(word []rune, prefix bool) bool {
+	if len(word) < len(r.condition) || len(word) <= utf8.RuneCountInString(r.strip) {
+		return false
+	}
+
+	if prefix && !strings.HasPrefix(string(word), r.strip) || !prefix && !strings.HasSuffix(string(word), r.strip) {
+		return false
+	}
+
+	offset := 0
+	if !prefix {
+		offset = len(word) - len(r.condition)
+	}
+
+	for i, c := range r.condition {
+		if !c.matches(word[offset+i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches checks if a letter meets the condition
+func (c affixCondition) matches(r rune) bool {
+	if c.any {
+		return true
+	}
+
+	return strings.ContainsRune(c.letters, r) != c.negate
+}
+
+// isNumber checks if a string is made up only of digits
+func isNumber(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
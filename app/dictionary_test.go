@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestSuggest(t *testing.T) {
+	d := NewDictionary()
+	d.Add("the", "receive", "relieve", "Paris", "spelling", "ten")
+
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"teh", []string{"the", "ten"}},
+		{"recieve", []string{"receive", "relieve"}},
+		{"Spelign", []string{"spelling"}},
+		{"paris", []string{"Paris"}},
+		{"xyzzy", nil},
+	}
+
+	for _, test := range tests {
+		if got := d.Suggest(test.word, 3); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", test.word, got, test.want)
+		}
+	}
+}
+
+func TestSpellingLookups(t *testing.T) {
+	d := NewDictionary()
+	d.Add("word")
+
+	p := SpellingProcessor{
+		Message:      UseSpellingProcessor.Message,
+		Dictionaries: []*Dictionary{d},
+		Suggestions:  DefaultSuggestions,
+		lookups:      newSuggestionLookups(),
+	}
+
+	if got := p.suggest("wrod"); !reflect.DeepEqual(got, []string{"word"}) {
+		t.Errorf("suggest(wrod) = %v", got)
+	}
+	if got := p.suggest("Wrod"); !reflect.DeepEqual(got, []string{"word"}) {
+		t.Errorf("suggest(Wrod) = %v", got)
+	}
+	if len(p.lookups.words) != 1 {
+		t.Errorf("looked up %d words, want 1", len(p.lookups.words))
+	}
+
+	for i := 0; len(p.lookups.words) < MaxSuggestionLookups; i++ {
+		p.lookups.words[fmt.Sprintf("filler%d", i)] = nil
+	}
+	if got := p.suggest("wodr"); got != nil {
+		t.Errorf("suggest(wodr) = %v after the lookups ran out", got)
+	}
+}
+
+func TestBundledDictionary(t *testing.T) {
+	d, err := LoadDictionary(FindDictionary(DefaultDictionary, ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, word := range []string{"café", "Café", "Brian", "Larry", "march", "may", "Monday"} {
+		if !d.Contains(word) {
+			t.Errorf("Contains(%q) = false, want true", word)
+		}
+	}
+	for _, word := range []string{"brian", "larry", "monday"} {
+		if d.Contains(word) {
+			t.Errorf("Contains(%q) = true, want false", word)
+		}
+	}
+}
+
+func TestFindDictionary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dictionary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "words.txt")
+	if err := ioutil.WriteFile(path, []byte("word\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FindDictionary("words.txt", filepath.Join(dir, "missing"), dir); got != path {
+		t.Errorf("FindDictionary() = %q, want %q", got, path)
+	}
+	if got := FindDictionary("missing.txt", dir); got != "missing.txt" {
+		t.Errorf("FindDictionary() = %q for a missing file", got)
+	}
+}
+
+func TestSpellingWithoutDictionary(t *testing.T) {
+	c := SpellingProcessor{Message: UseSpellingProcessor.Message}.Process(rules.NewChunk(0, "Every wrod is fine."))
+	if len(c.Matches) != 0 {
+		t.Errorf("got %d matches without a dictionary, want 0", len(c.Matches))
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MaxDocumentSize limits how much XML is read from inside a document archive
+// and how much text can be extracted from it
+const MaxDocumentSize = 50 << 20
+
+// maxODTSpaces is the most spaces a single <text:s> element can stand for
+const maxODTSpaces = 1000
+
+// errDocumentTooLarge is given when a document holds more text than
+// MaxDocumentSize
+var errDocumentTooLarge = fmt.Errorf("reading document: the text is larger than %d bytes", MaxDocumentSize)
+
+// Namespaces used by the document formats
+const (
+	wordNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	odtNamespace  = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// IsDocument checks if a file is a word processor document which has its
+// text extracted rather than being read as is
+func IsDocument(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".docx", ".odt":
+		return true
+	}
+
+	return false
+}
+
+// ExtractDocument pulls the text out of .docx and .odt files with one
+// paragraph, heading or list item per line. Any other file is returned as is.
+func ExtractDocument(filename string, data []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".docx":
+		return extractFromArchive(data, "word/document.xml", extractDOCX)
+	case ".odt":
+		return extractFromArchive(data, "content.xml", extractODT)
+	}
+
+	return string(data), nil
+}
+
+// extractFromArchive opens the zip archive of a document and runs the
+// extractor over the named XML file inside it
+func extractFromArchive(data []byte, name string, extract func(io.Reader) (string, error)) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("reading document: %s", err)
+	}
+
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+
+		r, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer r.Close()
+
+		return extract(io.LimitReader(r, MaxDocumentSize))
+	}
+
+	return "", fmt.Errorf("reading document: %s is missing", name)
+}
+
+// extractDOCX pulls the paragraphs out of a Word document.xml. Headings and
+// list items are paragraphs in Word so they all end up on their own line.
+func extractDOCX(r io.Reader) (string, error) {
+	var buffer bytes.Buffer
+
+	inText := false
+	d := xml.NewDecoder(r)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab", "br", "cr":
+				buffer.WriteString(" ")
+			}
+		case xml.EndElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				buffer.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				buffer.Write(t)
+			}
+		}
+
+		if buffer.Len() > MaxDocumentSize {
+			return "", errDocumentTooLarge
+		}
+	}
+
+	return buffer.String(), nil
+}
+
+// extractODT pulls the paragraphs and headings out of an OpenDocument
+// content.xml. List items hold their own paragraphs so they end up on their
+// own line too. Paragraphs inside others, such as footnotes, are kept on the
+// same line as the paragraph holding them.
+func extractODT(r io.Reader) (string, error) {
+	var buffer bytes.Buffer
+
+	depth := 0
+	d := xml.NewDecoder(r)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != odtNamespace {
+				continue
+			}
+
+			switch t.Name.Local {
+			case "p", "h":
+				if depth > 0 {
+					buffer.WriteString(" ")
+				}
+				depth++
+			case "s":
+				buffer.WriteString(strings.Repeat(" ", odtSpaceCount(t)))
+			case "tab", "line-break":
+				buffer.WriteString(" ")
+			}
+		case xml.EndElement:
+			if t.Name.Space != odtNamespace {
+				continue
+			}
+
+			if t.Name.Local == "p" || t.Name.Local == "h" {
+				depth--
+				if depth == 0 {
+					buffer.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			if depth > 0 {
+				buffer.Write(t)
+			}
+		}
+
+		if buffer.Len() > MaxDocumentSize {
+			return "", errDocumentTooLarge
+		}
+	}
+
+	return buffer.String(), nil
+}
+
+// odtSpaceCount reads how many spaces a <text:s> element stands for up to
+// maxODTSpaces
+func odtSpaceCount(t xml.StartElement) int {
+	for _, attr := range t.Attr {
+		if attr.Name.Local == "c" {
+			count, err := strconv.Atoi(attr.Value)
+			if err == nil && count > maxODTSpaces {
+				return maxODTSpaces
+			}
+			if err == nil && count > 0 {
+				return count
+			}
+		}
+	}
+
+	return 1
+}
+
+// readDocument reads a file from disk extracting the text from documents
+func readDocument(filename string) (string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+
+	return ExtractDocument(filename, data)
+}
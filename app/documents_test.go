@@ -0,0 +1,96 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// buildArchive zips up a single file the way a document would hold it
+func buildArchive(t *testing.T, name string, content string) []byte {
+	var buffer bytes.Buffer
+
+	archive := zip.NewWriter(&buffer)
+	w, err := archive.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestExtractDOCX(t *testing.T) {
+	xml := `<w:document xmlns:w="` + wordNamespace + `"><w:body>` +
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>A heading</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>First part</w:t></w:r><w:r><w:tab/><w:t>and more.</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>Café &amp; bar.</w:t></w:r></w:p>` +
+		`</w:body></w:document>`
+
+	text, err := ExtractDocument("notes.docx", buildArchive(t, "word/document.xml", xml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "A heading\nFirst part and more.\nCafé & bar.\n"
+	if text != want {
+		t.Errorf("ExtractDocument() = %q, want %q", text, want)
+	}
+}
+
+func TestExtractODT(t *testing.T) {
+	xml := `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="` + odtNamespace + `"><office:body><office:text>` +
+		`<text:h>A heading</text:h>` +
+		`<text:p>Two<text:s text:c="2"/>spaces<text:tab/>and a tab.</text:p>` +
+		`<text:list><text:list-item><text:p>An item</text:p></text:list-item></text:list>` +
+		`<text:p>Noted<text:note><text:note-body><text:p>a footnote</text:p></text:note-body></text:note>.</text:p>` +
+		`</office:text></office:body></office:document-content>`
+
+	text, err := ExtractDocument("notes.odt", buildArchive(t, "content.xml", xml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "A heading\nTwo  spaces and a tab.\nAn item\nNoted a footnote.\n"
+	if text != want {
+		t.Errorf("ExtractDocument() = %q, want %q", text, want)
+	}
+}
+
+func TestExtractODTLimitsSpaces(t *testing.T) {
+	open := `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="` + odtNamespace + `"><office:body><office:text><text:p>`
+	end := `</text:p></office:text></office:body></office:document-content>`
+
+	text, err := ExtractDocument("huge.odt", buildArchive(t, "content.xml", open+`a<text:s text:c="2000000000"/>b`+end))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(text) != maxODTSpaces+3 {
+		t.Errorf("a single space element gave %d bytes of text, want %d", len(text), maxODTSpaces+3)
+	}
+
+	many := strings.Repeat(`<text:s text:c="1000"/>`, MaxDocumentSize/maxODTSpaces+1)
+	if _, err := ExtractDocument("huge.odt", buildArchive(t, "content.xml", open+many+end)); err != errDocumentTooLarge {
+		t.Errorf("ExtractDocument() error = %v, want %v", err, errDocumentTooLarge)
+	}
+}
+
+func TestExtractDocumentErrors(t *testing.T) {
+	if _, err := ExtractDocument("broken.docx", []byte("not a zip")); err == nil {
+		t.Error("a file which isn't a zip archive gave no error")
+	}
+
+	if _, err := ExtractDocument("empty.odt", buildArchive(t, "other.xml", "<x/>")); err == nil {
+		t.Error("an archive without content.xml gave no error")
+	}
+
+	if text, _ := ExtractDocument("notes.txt", []byte("plain")); text != "plain" {
+		t.Errorf("a text file gave %q, want it as is", text)
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"write-better/rules"
+)
+
+// DiffContext is the number of unchanged lines shown around each diff hunk
+const DiffContext = 3
+
+// Edit is a single replacement of the text between two absolute byte offsets
+type Edit struct {
+	// Start is the absolute byte offset where the replaced text begins
+	Start int `json:"start"`
+	// End is the absolute byte offset where the replaced text ends
+	End int `json:"end"`
+	// Original is the text being replaced
+	Original string `json:"original"`
+	// Replacement is the text put in its place
+	Replacement string `json:"replacement"`
+	// Label is the type of processor the edit came from
+	Label string `json:"label"`
+}
+
+// ByEditStart is a sorting mechanism for sorting edits by position
+type ByEditStart []Edit
+
+func (e ByEditStart) Len() int           { return len(e) }
+func (e ByEditStart) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e ByEditStart) Less(i, j int) bool { return e[i].Start < e[j].Start }
+
+// CollectEdits turns matches with suggestions into edits using the first
+// suggestion. When labels are given only matches with those labels are used,
+// otherwise only matches with exactly one suggestion from processors marked
+// SafeFix are. Other suggestions like "try" for "attempt" depend on how the
+// word is used so they are left for the writer to pick.
+func CollectEdits(matches []MatchResult, labels []string) []Edit {
+	var edits []Edit
+
+	for _, match := range matches {
+		if len(match.Suggestions) == 0 {
+			continue
+		}
+
+		if len(labels) > 0 && !containsString(labels, match.Label) {
+			continue
+		}
+
+		if len(labels) == 0 && (len(match.Suggestions) != 1 || !isSafeFix(match.Label)) {
+			continue
+		}
+
+		edits = append(edits, Edit{
+			Start:       match.start,
+			End:         match.end,
+			Original:    match.Match,
+			Replacement: match.Suggestions[0],
+			Label:       match.Label,
+		})
+	}
+
+	return edits
+}
+
+// isSafeFix checks if the processor with the label is marked SafeFix
+func isSafeFix(label string) bool {
+	info, ok := rules.LookupProcessor(label)
+	return ok && info.SafeFix
+}
+
+// ApplyEdits rewrites the text with the edits applied. The edits refer to
+// offsets in the original text so the result is built up in order rather than
+// changing the text in place. An edit overlapping one before it is skipped.
+// The edits which were applied are returned along with the new text.
+func ApplyEdits(text string, edits []Edit) (string, []Edit) {
+	var buffer bytes.Buffer
+	var applied []Edit
+
+	sorted := make([]Edit, len(edits))
+	copy(sorted, edits)
+	sort.Stable(ByEditStart(sorted))
+
+	pos := 0
+	for _, edit := range sorted {
+		if edit.Start < pos || edit.End > len(text) || edit.Start > edit.End {
+			continue
+		}
+
+		buffer.WriteString(text[pos:edit.Start])
+		buffer.WriteString(edit.Replacement)
+		pos = edit.End
+
+		applied = append(applied, edit)
+	}
+
+	buffer.WriteString(text[pos:])
+
+	return buffer.String(), applied
+}
+
+// diffOp is a single line in a diff. Kind is ' ' for an unchanged line, '-'
+// for a removed line or '+' for an added one. A and B are the number of lines
+// of the old and new text seen before this one.
+type diffOp struct {
+	Kind byte
+	Line string
+	A    int
+	B    int
+}
+
+// UnifiedDiff builds a unified diff by line of the changes the edits make to
+// the text. The edits need to be sorted without overlapping like the ones
+// ApplyEdits gives back. Only the lines each edit touches are compared so a
+// long text with a few fixes stays quick.
+func UnifiedDiff(oldName string, newName string, text string, edits []Edit) string {
+	var buffer bytes.Buffer
+
+	hunks := diffHunks(diffEdits(text, edits))
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	fmt.Fprintf(&buffer, "--- %s\n+++ %s\n", oldName, newName)
+
+	for _, hunk := range hunks {
+		var oldLen, newLen int
+		for _, op := range hunk {
+			if op.Kind != '+' {
+				oldLen++
+			}
+			if op.Kind != '-' {
+				newLen++
+			}
+		}
+
+		fmt.Fprintf(&buffer, "@@ -%s +%s @@\n", hunkRange(hunk[0].A, oldLen), hunkRange(hunk[0].B, newLen))
+
+		for _, op := range hunk {
+			buffer.WriteByte(op.Kind)
+			buffer.WriteString(op.Line)
+			if !strings.HasSuffix(op.Line, "\n") {
+				buffer.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return buffer.String()
+}
+
+// splitLines breaks text into lines keeping their line endings
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffEdits lists every line of the text along with the changes the edits
+// make. Edits touching the same lines are grouped and only the lines of each
+// group are compared with what they become.
+func diffEdits(text string, edits []Edit) []diffOp {
+	var ops []diffOp
+
+	lines := splitLines(text)
+
+	// starts[i] is the offset where line i begins
+	starts := make([]int, len(lines)+1)
+	for i, line := range lines {
+		starts[i+1] = starts[i] + len(line)
+	}
+
+	// lineAt gives the line holding the offset. The end of the text belongs
+	// to the last line when it has no line ending.
+	lineAt := func(offset int) int {
+		line := sort.Search(len(starts), func(i int) bool { return starts[i] > offset }) - 1
+		if line == len(lines) && line > 0 && !strings.HasSuffix(lines[line-1], "\n") {
+			line--
+		}
+
+		return line
+	}
+
+	// shift is how many more lines the new text has than the old text so far
+	line, shift := 0, 0
+
+	for i := 0; i < len(edits); {
+		first := lineAt(edits[i].Start)
+		last := lineAt(edits[i].End) + 1
+
+		var changed bytes.Buffer
+		pos := starts[first]
+
+		for ; i < len(edits) && lineAt(edits[i].Start) < last; i++ {
+			changed.WriteString(text[pos:edits[i].Start])
+			changed.WriteString(edits[i].Replacement)
+			pos = edits[i].End
+
+			if end := lineAt(pos) + 1; end > last {
+				last = end
+			}
+		}
+
+		if last > len(lines) {
+			last = len(lines)
+		}
+		changed.WriteString(text[pos:starts[last]])
+
+		for ; line < first; line++ {
+			ops = append(ops, diffOp{Kind: ' ', Line: lines[line], A: line, B: line + shift})
+		}
+
+		var newLines []string
+		if changed.Len() > 0 {
+			newLines = splitLines(changed.String())
+		}
+
+		for _, op := range diffLines(lines[first:last], newLines) {
+			op.A += first
+			op.B += first + shift
+			ops = append(ops, op)
+		}
+
+		shift += len(newLines) - (last - first)
+		line = last
+	}
+
+	for ; line < len(lines); line++ {
+		ops = append(ops, diffOp{Kind: ' ', Line: lines[line], A: line, B: line + shift})
+	}
+
+	return ops
+}
+
+// diffLines finds the changes between two short lists of lines using the
+// longest common subsequence. Matching lines at the start and end are trimmed
+// first to keep the table small.
+func diffLines(a []string, b []string) []diffOp {
+	var ops []diffOp
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{Kind: ' ', Line: a[i], A: i, B: i})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of midA[i:]
+	// and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, diffOp{Kind: ' ', Line: midA[i], A: prefix + i, B: prefix + j})
+			i++
+			j++
+		case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{Kind: '-', Line: midA[i], A: prefix + i, B: prefix + j})
+			i++
+		default:
+			ops = append(ops, diffOp{Kind: '+', Line: midB[j], A: prefix + i, B: prefix + j})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		ai, bi := len(a)-suffix+k, len(b)-suffix+k
+		ops = append(ops, diffOp{Kind: ' ', Line: a[ai], A: ai, B: bi})
+	}
+
+	return ops
+}
+
+// diffHunks groups the changed lines with the unchanged lines around them.
+// Changes close enough to share their context end up in the same hunk.
+func diffHunks(ops []diffOp) [][]diffOp {
+	var hunks [][]diffOp
+
+	i := 0
+	for i < len(ops) {
+		if ops[i].Kind == ' ' {
+			i++
+			continue
+		}
+
+		start := i - DiffContext
+		if start < 0 {
+			start = 0
+		}
+
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != ' ' {
+				end++
+				continue
+			}
+
+			run := 0
+			for end+run < len(ops) && ops[end+run].Kind == ' ' {
+				run++
+			}
+
+			if end+run == len(ops) || run > 2*DiffContext {
+				if run > DiffContext {
+					run = DiffContext
+				}
+				end += run
+				break
+			}
+
+			end += run
+		}
+
+		hunks = append(hunks, ops[start:end])
+		i = end
+	}
+
+	return hunks
+}
+
+// hunkRange formats the start and length of a hunk for its header
+func hunkRange(start int, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		text  string
+		edits []Edit
+		want  string
+	}{
+		{
+			"one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\n",
+			[]Edit{{Start: 14, End: 18, Replacement: "4"}},
+			"--- a\n+++ b\n@@ -1,7 +1,7 @@\n one\n two\n three\n-four\n+4\n five\n six\n seven\n",
+		},
+		{
+			"one\ntwo",
+			[]Edit{{Start: 0, End: 3, Replacement: "1"}, {Start: 7, End: 7, Replacement: "\n"}},
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n-one\n+1\n-two\n\\ No newline at end of file\n+two\n",
+		},
+		{
+			"one\ntwo\nthree\n",
+			[]Edit{{Start: 3, End: 4, Replacement: " "}},
+			"--- a\n+++ b\n@@ -1,3 +1,2 @@\n-one\n-two\n+one two\n three\n",
+		},
+		{
+			"one\n",
+			[]Edit{{Start: 0, End: 3, Replacement: "one"}},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		if got := UnifiedDiff("a", "b", test.text, test.edits); got != test.want {
+			t.Errorf("UnifiedDiff(%q, %v) =\n%s\nwant\n%s", test.text, test.edits, got, test.want)
+		}
+	}
+}
+
+func TestCollectEditsDefault(t *testing.T) {
+	profile, err := DefaultConfig().Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"I could of gone.", "I could have gone."},
+		{"We had a discussion about the budget.", "We had a discussion about the budget."},
+		{"She made a payment of five dollars.", "She made a payment of five dollars."},
+		{"Make an attempt at it.", "Make an attempt at it."},
+	}
+
+	for _, test := range tests {
+		chunks, _, err := Analyze(test.text, "text", profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, _ := ApplyEdits(test.text, CollectEdits(FlattenMatches(test.text, chunks), nil))
+		if got != test.want {
+			t.Errorf("fixing %q gave %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"write-better/rules"
+)
+
+// Limits for the word frequency report
+const (
+	// FrequencyTopTerms is the number of words and phrases listed
+	FrequencyTopTerms = 10
+	// OverusedMinimum is the fewest times a word must be used to be overused
+	OverusedMinimum = 4
+	// OverusedRate is the share of all words a single word must pass to be
+	// overused
+	OverusedRate = 0.02
+)
+
+// Stopwords are common words left out of the word frequency report since
+// every text uses them often
+var Stopwords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		a about above after again against all am an and any are as at be
+		because been before being below between both but by can could did do
+		does doing down during each few for from further had has have having
+		he her here hers herself him himself his how i if in into is it its
+		itself just me more most my myself no nor not now of off on once only
+		or other our ours ourselves out over own same she should so some such
+		than that the their theirs them themselves then there these they this
+		those through to too under until up very was we were what when where
+		which while who whom why will with would you your yours yourself
+		yourselves also may might must shall let us one it's i'm don't can't
+		won't isn't aren't wasn't weren't didn't doesn't that's there's
+		they're we're you're i've we've you've they've i'd we'd you'd he's
+		she's`) {
+		Stopwords[word] = true
+	}
+}
+
+// TermCount is how often a word or phrase is used
+type TermCount struct {
+	// Term is the word or phrase in lower case
+	Term string `json:"term"`
+	// Count is the number of times it is used
+	Count int `json:"count"`
+	// Overused marks words used far more often than expected for the
+	// length of the text
+	Overused bool `json:"overused"`
+}
+
+// ByCount is a sorting mechanism for sorting terms by how often they're used
+type ByCount []TermCount
+
+func (t ByCount) Len() int      { return len(t) }
+func (t ByCount) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+func (t ByCount) Less(i, j int) bool {
+	if t[i].Count != t[j].Count {
+		return t[i].Count > t[j].Count
+	}
+
+	return t[i].Term < t[j].Term
+}
+
+// WordFrequency reports the most used words and phrases in a text
+type WordFrequency struct {
+	// Words are the most used words leaving out stopwords
+	Words []TermCount `json:"words"`
+	// Phrases are the most used two and three word phrases which don't start
+	// or end with a stopword
+	Phrases []TermCount `json:"phrases"`
+	// Overused are every word used far more often than expected
+	Overused []TermCount `json:"overused"`
+}
+
+// GetWordFrequency counts the words and phrases in the chunked text. Phrases
+// don't cross sentences. A word is overused when it makes up more than
+// OverusedRate of the words counted in the summary.
+func GetWordFrequency(chunks rules.Chunks, summary Summary) WordFrequency {
+	result := WordFrequency{Words: []TermCount{}, Phrases: []TermCount{}, Overused: []TermCount{}}
+
+	words := make(map[string]int)
+	phrases := make(map[string]int)
+
+	for _, chunk := range chunks {
+		// Curly apostrophes are straightened so "don’t" stays one word
+		var sentence []string
+		for _, word := range Words(NormalizeForMatching(chunk.Data)) {
+			sentence = append(sentence, strings.ToLower(strings.Trim(word, "'")))
+		}
+
+		for i, word := range sentence {
+			if isFrequencyWord(word) {
+				words[word]++
+			}
+
+			for n := 2; n <= 3 && i+n <= len(sentence); n++ {
+				if isFrequencyWord(sentence[i]) && isFrequencyWord(sentence[i+n-1]) {
+					phrases[strings.Join(sentence[i:i+n], " ")]++
+				}
+			}
+		}
+	}
+
+	limit := float64(summary["words"]) * OverusedRate
+	for term, count := range words {
+		tc := TermCount{Term: term, Count: count}
+		tc.Overused = count >= OverusedMinimum && float64(count) > limit
+
+		result.Words = append(result.Words, tc)
+		if tc.Overused {
+			result.Overused = append(result.Overused, tc)
+		}
+	}
+
+	// A phrase has to be repeated to be worth mentioning
+	for term, count := range phrases {
+		if count > 1 {
+			result.Phrases = append(result.Phrases, TermCount{Term: term, Count: count})
+		}
+	}
+
+	sort.Sort(ByCount(result.Words))
+	sort.Sort(ByCount(result.Phrases))
+	sort.Sort(ByCount(result.Overused))
+
+	if len(result.Words) > FrequencyTopTerms {
+		result.Words = result.Words[:FrequencyTopTerms]
+	}
+	if len(result.Phrases) > FrequencyTopTerms {
+		result.Phrases = result.Phrases[:FrequencyTopTerms]
+	}
+
+	return result
+}
+
+// isFrequencyWord checks if a word is counted in the frequency report. Stop
+// words, single letters and numbers are left out.
+func isFrequencyWord(word string) bool {
+	if len([]rune(word)) < 2 || Stopwords[word] {
+		return false
+	}
+
+	for _, r := range word {
+		if IsAlpha(r) {
+			return true
+		}
+	}
+
+	return false
+}
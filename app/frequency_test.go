@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordFrequency(t *testing.T) {
+	text := "Cats couldn’t sleep. Cats couldn't eat. The garden cats ran home. Garden cats rest."
+
+	chunks, summary, err := NewSentenceChunker(text).Chunk()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := GetWordFrequency(chunks, summary)
+
+	wantWords := []TermCount{
+		{"cats", 4, true},
+		{"couldn't", 2, false},
+		{"garden", 2, false},
+		{"eat", 1, false},
+		{"home", 1, false},
+		{"ran", 1, false},
+		{"rest", 1, false},
+		{"sleep", 1, false},
+	}
+	if !reflect.DeepEqual(got.Words, wantWords) {
+		t.Errorf("words are %v, want %v", got.Words, wantWords)
+	}
+
+	wantPhrases := []TermCount{{"cats couldn't", 2, false}, {"garden cats", 2, false}}
+	if !reflect.DeepEqual(got.Phrases, wantPhrases) {
+		t.Errorf("phrases are %v, want %v", got.Phrases, wantPhrases)
+	}
+
+	if len(got.Overused) != 1 || got.Overused[0].Term != "cats" {
+		t.Errorf("overused words are %v, want cats", got.Overused)
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"write-better/rules"
+)
+
+// GrammarRule is a single grammar check. The pattern finds text which may be
+// a mistake and the check looks at it and the text around it to decide.
+type GrammarRule struct {
+	// Pattern finds the text to check
+	Pattern *regexp.Regexp
+	// Group is the submatch holding the mistake which is what gets matched
+	Group int
+	// Check gives the message and replacement for a mistake or false when the
+	// text is fine
+	Check GrammarCheck
+}
+
+// GrammarCheck decides if the text a grammar rule found is a mistake
+type GrammarCheck func(m GrammarMatch) (string, string, bool)
+
+// GrammarMatch is the text a grammar rule found along with the text around it
+// in the chunk
+type GrammarMatch struct {
+	// Groups are the submatches of the rule's pattern
+	Groups []string
+	// Before is the text before the match
+	Before string
+	// After is the text after the match
+	After string
+}
+
+// NewGrammarRule is a convenience function to build a GrammarRule
+func NewGrammarRule(expr string, group int, check GrammarCheck) GrammarRule {
+	return GrammarRule{Pattern: regexp.MustCompile(expr), Group: group, Check: check}
+}
+
+// replaceWith builds a check which always replaces the mistake with the same
+// word
+func replaceWith(msg string, replacement string) GrammarCheck {
+	return func(m GrammarMatch) (string, string, bool) {
+		return msg, replacement, true
+	}
+}
+
+// firstWord gives the first word of the text in lowercase
+func firstWord(text string) string {
+	text = strings.TrimLeftFunc(text, unicode.IsSpace)
+	if end := strings.IndexFunc(text, func(r rune) bool { return !IsAlpha(r) && r != '\'' }); end >= 0 {
+		text = text[:end]
+	}
+
+	return strings.ToLower(text)
+}
+
+// lastWord gives the last word of the text in lowercase
+func lastWord(text string) string {
+	text = strings.TrimRightFunc(text, unicode.IsSpace)
+	start := strings.LastIndexFunc(text, func(r rune) bool { return !IsAlpha(r) && r != '\'' })
+	if start >= 0 {
+		_, size := utf8.DecodeRuneInString(text[start:])
+		text = text[start+size:]
+	}
+
+	return strings.ToLower(text)
+}
+
+// grammarRules are the built in grammar checks. Each only looks for the
+// mistake where the words around it make it clear.
+var grammarRules = []GrammarRule{
+	NewGrammarRule(`(?i)\b(your)\s+(welcome|not|going|a|an|the|sure)\b`, 1,
+		replaceWith("Use 'you're', short for 'you are', here.", "you're")),
+	NewGrammarRule(`(?i)\b(you're)\s+own\b`, 1,
+		replaceWith("Use 'your', meaning belonging to you, here.", "your")),
+	NewGrammarRule(`(?i)\b(its)\s+(a|an|the|been|not|going|getting|also)\b`, 1,
+		replaceWith("Use 'it's', short for 'it is' or 'it has', here.", "it's")),
+	NewGrammarRule(`(?i)\b(it's)\s+own\b`, 1,
+		replaceWith("Use 'its', meaning belonging to it, here.", "its")),
+	NewGrammarRule(`(?i)\b(more|less|fewer|better|worse|rather|other|greater|smaller|larger|bigger|higher|lower|faster|slower|easier|harder|cheaper|stronger|weaker)\s+(then)\b`, 2,
+		replaceWith("Use 'than' to compare things. 'Then' is about time.", "than")),
+	NewGrammarRule(`(?i)\b(and|since|until|back)\s+(than)\b`, 2,
+		replaceWith("Use 'then' for time or order. 'Than' compares things.", "then")),
+	NewGrammarRule(`(?i)\b(an|the|no|any|side|positive|negative|adverse|desired|opposite|net|ripple|domino|butterfly|placebo|greenhouse|lasting|profound|significant)\s+(affect)s?\b`, 2,
+		replaceWith("Use 'effect' for the noun, the result of a change. 'Affect' is the verb.", "effect")),
+	// Modals are left out since "could effect change" means bringing it about
+	NewGrammarRule(`(?i)\b(does|did|doesn't|didn't|negatively|positively|adversely|directly|greatly|seriously)\s+(effect(s|ed|ing)?)\b`, 2,
+		func(m GrammarMatch) (string, string, bool) {
+			return "Use 'affect' for the verb, to change something. 'Effect' is the noun.", "a" + m.Groups[2][1:], true
+		}),
+	NewGrammarRule(`(?i)\b(be|been|being|is|are|was|were)\s+(effected)\s+by\b`, 2,
+		replaceWith("Use 'affected' for something changed by another. 'Effected' means brought about.", "affected")),
+	NewGrammarRule(`(?i)\b(could|would|should|might|must)\s+(of)\b`, 2,
+		func(m GrammarMatch) (string, string, bool) {
+			// "could of course" is fine
+			if firstWord(m.After) == "course" {
+				return "", "", false
+			}
+
+			modal := strings.ToLower(m.Groups[1])
+			return fmt.Sprintf("Use '%s have' or '%s've'. 'Of' isn't a verb.", modal, modal), "have", true
+		}),
+	// A single space since Markdown inline code is dropped leaving two as in
+	// "an `init` function"
+	NewGrammarRule(`(?i)\b(a|an)\s([\pL][\pL'-]*)`, 1, checkArticle),
+	NewGrammarRule(`(?i)\b(he|she|it|i|you|we|they)\s+(am|is|are|was|were|has|have|does|do|doesn't|don't|isn't|aren't|wasn't|weren't)\b`, 2, checkAgreement),
+	NewGrammarRule(`(\w?)([.,;:!?]{2,})(\w?)`, 2, checkPunctuation),
+}
+
+// consonantSounds are the starts of words which begin with a vowel but are
+// said with a consonant sound as in "a unit"
+var consonantSounds = []string{
+	"eu", "ewe", "one-", "ubi", "uku", "unan", "unia", "unic", "unif", "unil", "unio", "uniq", "unis", "unit", "univ",
+	"uran", "ure", "urin", "usa", "use", "usu", "ute", "uti", "uto",
+}
+
+// vowelSounds are the starts of words which begin with a consonant but are
+// said with a vowel sound as in "an hour"
+var vowelSounds = []string{"heir", "honest", "honor", "honour", "hour"}
+
+// articleLabels are words which follow "a" when it is a label as in "a and b"
+// rather than an article
+var articleLabels = []string{"a", "an", "and", "are", "as", "at", "by", "for", "if", "in", "is", "of", "on", "or", "so", "the", "to", "was", "with"}
+
+// Article gives the indefinite article, "a" or "an", to use before a word
+// based on how it is said. An empty article means it can't tell.
+func Article(word string) string {
+	lower := strings.ToLower(word)
+	first, _ := utf8.DecodeRuneInString(lower)
+
+	// Acronyms are said in too many ways to guess
+	if utf8.RuneCountInString(word) > 1 && strings.ToUpper(word) == word {
+		return ""
+	}
+
+	// Single letters and words like "X-ray" are said by the letter's name
+	if utf8.RuneCountInString(word) == 1 || strings.HasPrefix(lower[utf8.RuneLen(first):], "-") {
+		if strings.ContainsRune("aefhilmnorsx", first) {
+			return "an"
+		}
+		return "a"
+	}
+
+	if lower == "one" || lower == "once" || lower == "oneself" {
+		return "a"
+	}
+
+	for _, prefix := range consonantSounds {
+		if strings.HasPrefix(lower, prefix) {
+			return "a"
+		}
+	}
+
+	for _, prefix := range vowelSounds {
+		if strings.HasPrefix(lower, prefix) {
+			return "an"
+		}
+	}
+
+	if strings.ContainsRune("aeiou", first) {
+		return "an"
+	}
+
+	if unicode.IsLetter(first) {
+		return "a"
+	}
+
+	return ""
+}
+
+// checkArticle checks that "a" comes before consonant sounds and "an" before
+// vowel sounds
+func checkArticle(m GrammarMatch) (string, string, bool) {
+	groups := m.Groups
+	article := strings.ToLower(groups[1])
+	word := strings.ToLower(groups[2])
+
+	if containsString(articleLabels, word) {
+		return "", "", false
+	}
+
+	want := Article(groups[2])
+	if want == "" || want == article {
+		return "", "", false
+	}
+
+	if want == "an" {
+		return fmt.Sprintf("Use 'an' before '%s' since it starts with a vowel sound.", groups[2]), want, true
+	}
+
+	return fmt.Sprintf("Use 'a' before '%s' since it starts with a consonant sound.", groups[2]), want, true
+}
+
+// agreement maps the verbs which don't agree with a subject pronoun to the
+// ones which do. "Were" is left alone since "if it were" is fine.
+var agreement = map[string]map[string]string{
+	"singular": {
+		"am": "is", "are": "is", "have": "has", "do": "does", "don't": "doesn't", "aren't": "isn't",
+	},
+	"i": {
+		"is": "am", "are": "am", "has": "have", "does": "do", "doesn't": "don't",
+	},
+	"plural": {
+		"am": "are", "is": "are", "was": "were", "has": "have", "does": "do", "doesn't": "don't", "isn't": "aren't", "wasn't": "weren't",
+	},
+}
+
+// pronounNumber groups the subject pronouns by the verbs they take
+var pronounNumber = map[string]string{
+	"he": "singular", "she": "singular", "it": "singular",
+	"i":   "i",
+	"you": "plural", "we": "plural", "they": "plural",
+}
+
+// clauseConjunctions are words which start a new clause so a pronoun right
+// after them is its subject. "That" is left out since "that he have" is fine.
+var clauseConjunctions = []string{
+	"after", "although", "and", "as", "because", "before", "but", "if", "nor", "once", "or", "since", "so", "than",
+	"though", "unless", "until", "when", "whenever", "where", "whereas", "whether", "while", "yet",
+}
+
+// clausePunctuation are marks which can come right before the start of a
+// clause
+const clausePunctuation = ",;:()[]\"'“‘—–-"
+
+// checkAgreement checks that a verb agrees with the subject pronoun before it.
+// The pronoun has to start the sentence or a clause since it can be the
+// object otherwise as in "does it have" or "let them do".
+func checkAgreement(m GrammarMatch) (string, string, bool) {
+	groups := m.Groups
+
+	// Only the capital "I" is the pronoun
+	if groups[1] == "i" {
+		return "", "", false
+	}
+
+	if !startsClause(m.Before) {
+		return "", "", false
+	}
+
+	pronoun := strings.ToLower(groups[1])
+	verb := strings.ToLower(groups[2])
+
+	fix, ok := agreement[pronounNumber[pronoun]][verb]
+	if !ok {
+		return "", "", false
+	}
+
+	return fmt.Sprintf("'%s' doesn't agree with '%s'. Use '%s'.", groups[2], groups[1], fix), fix, true
+}
+
+// startsClause checks if the text before a word ends where a sentence or
+// clause starts
+func startsClause(before string) bool {
+	before = strings.TrimRightFunc(before, unicode.IsSpace)
+	if before == "" {
+		return true
+	}
+
+	if last, _ := utf8.DecodeLastRuneInString(before); strings.ContainsRune(clausePunctuation, last) {
+		return true
+	}
+
+	return containsString(clauseConjunctions, lastWord(before))
+}
+
+// checkPunctuation checks for punctuation marks written twice in a row such as
+// ",," or "!!". Ellipses, marks after an abbreviation like "etc.," and ones
+// inside text like "1..10" or "std::string" are fine.
+func checkPunctuation(m GrammarMatch) (string, string, bool) {
+	groups := m.Groups
+	run := groups[2]
+
+	if groups[1] != "" && groups[3] != "" {
+		return "", "", false
+	}
+
+	var fix string
+	switch {
+	case strings.Trim(run, ".") == "":
+		if run != ".." {
+			return "", "", false
+		}
+		fix = "."
+	case run[0] == '.':
+		return "", "", false
+	case strings.Trim(run, "!?") == "":
+		if strings.Trim(run, run[:1]) != "" {
+			// "?!" asks a question with surprise
+			return "", "", false
+		}
+		fix = run[:1]
+	case strings.ContainsRune("!?", rune(run[0])):
+		fix = run[:1]
+	default:
+		fix = run[len(run)-1:]
+	}
+
+	return fmt.Sprintf("'%s' is doubled punctuation. Use '%s'.", run, fix), fix, true
+}
+
+// GrammarProcessor processes common grammar mistakes such as "your" for
+// "you're", "could of" or "a" before a vowel sound
+type GrammarProcessor struct {
+	// Message is the message given with a match when its rule has none where
+	// %s is replaced with the mistake
+	Message string
+	// Override gives Message for every match, even those whose rule has its
+	// own message, such as when a profile replaces the message
+	Override bool
+	// Rules are the grammar checks to run
+	Rules []GrammarRule
+}
+
+// UseGrammarProcessor is a convenience variable for referencing a GrammarProcessor
+var UseGrammarProcessor = GrammarProcessor{
+	Message: "'%s' may be a grammar mistake.",
+	Rules:   grammarRules,
+}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "grammar",
+		Name:        "Grammar",
+		Legend:      "Grammar Mistakes",
+		Description: "Common grammar mistakes like \"your\" for \"you're\", \"then\" for \"than\", \"could of\", \"a\" before a vowel sound, verbs which don't agree with their subject and doubled punctuation. They are easy to make when typing quickly and easy to miss when reading back.",
+		Message:     UseGrammarProcessor.Message,
+		Category:    "correctness",
+		Color:       "180, 40, 120",
+		SafeFix:     true,
+		New: func(r rules.Rule) rules.Processor {
+			return GrammarProcessor{
+				Message:  r.Message,
+				Override: r.MessageSet,
+				Rules:    UseGrammarProcessor.Rules,
+			}
+		},
+	})
+}
+
+// Process handles the processing for grammar mistake matches. Rules earlier in
+// the list win when mistakes overlap and inline code is skipped.
+func (p GrammarProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	normalized := NormalizeForMatching(c.Data)
+	taken := inlineCode.FindAllStringIndex(normalized, -1)
+
+	for _, rule := range p.Rules {
+		for _, found := range rule.Pattern.FindAllStringSubmatchIndex(normalized, -1) {
+			groups := make([]string, len(found)/2)
+			for i := range groups {
+				if found[2*i] >= 0 {
+					groups[i] = normalized[found[2*i]:found[2*i+1]]
+				}
+			}
+
+			msg, replacement, ok := rule.Check(GrammarMatch{
+				Groups: groups,
+				Before: normalized[:found[0]],
+				After:  normalized[found[1]:],
+			})
+			if !ok {
+				continue
+			}
+
+			span := found[2*rule.Group : 2*rule.Group+2]
+			if overlapsAny(taken, span) {
+				continue
+			}
+			taken = append(taken, span)
+
+			indices := RuneIndices(normalized, span)
+			match := RuneSlice(c.Data, indices[0], indices[1])
+
+			if msg == "" || p.Override {
+				msg = FormatMessage(p.Message, match)
+			}
+
+			m := rules.NewMatch(match, "grammar", indices, msg)
+			if replacement != "" {
+				m.Suggestions = append(m.Suggestions, MatchCase(match, replacement))
+			}
+
+			c.Matches = append(c.Matches, m)
+			c.Score += 1
+		}
+	}
+
+	return c
+}
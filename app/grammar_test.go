@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestGrammar(t *testing.T) {
+	tests := []struct {
+		data string
+		want []string
+	}{
+		{"I could of gone.", []string{"of"}},
+		{"It could of course work.", nil},
+		{"They is here.", []string{"is"}},
+		{"We left, and they is here.", []string{"is"}},
+		{"When she have time, we go.", []string{"have"}},
+		{"Does it have a name?", nil},
+		{"Let them do it.", nil},
+		{"We watched it do the work.", nil},
+		{"It is important that he have a chance.", nil},
+		{"This could effect change.", nil},
+		{"It doesn't effect the result.", []string{"effect"}},
+		{"It was a apple.", []string{"a"}},
+	}
+
+	for _, test := range tests {
+		c := UseGrammarProcessor.Process(rules.NewChunk(0, test.data))
+
+		var got []string
+		for _, m := range c.Matches {
+			got = append(got, m.Match)
+		}
+
+		if len(got) != len(test.want) {
+			t.Errorf("%q gave %q, want %q", test.data, got, test.want)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%q gave %q, want %q", test.data, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestGrammarMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"", "Use 'could have' or 'could've'. 'Of' isn't a verb."},
+		{"Check '%s'.", "Check 'of'."},
+	}
+
+	for _, test := range tests {
+		c := profileProcessor(t, "grammar", test.message).Process(rules.NewChunk(0, "I could of gone."))
+		if len(c.Matches) != 1 || c.Matches[0].Message != test.want {
+			t.Errorf("message %q gave %v, want %q", test.message, c.Matches, test.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import "write-better/rules"
+
+// Messages for the kinds of hedge processor phrases
+const (
+	hedgeMessage  = "'%s' hedges the statement. Say it with confidence or leave it out."
+	fillerMessage = "'%s' is filler which adds words without meaning. Try leaving it out."
+)
+
+// hedgePhrases are the phrases which soften a statement. Adverbs ending in
+// "ly" such as "probably" are left to the adverb processor so they aren't
+// counted twice.
+var hedgePhrases = []string{
+	"I think", "I believe", "I feel", "I guess", "I suppose", "in my opinion",
+	"sort of", "kind of", "it seems that", "it seems", "seems to", "appears to",
+	"somewhat", "perhaps", "maybe", "more or less", "to some extent",
+	"to a certain extent", "in a way",
+}
+
+// fillerPhrases are the empty phrases which add nothing to a statement.
+// The common intensifiers "very", "really" and "actually" are listed here
+// too while the rest such as "quite" are left to the weasel word processor.
+var fillerPhrases = []string{
+	"just", "very", "really", "actually", "in fact", "as a matter of fact",
+	"needless to say", "to be honest", "to tell the truth",
+	"for what it's worth", "all things considered",
+}
+
+// defaultHedgeEntries builds the phrase list entries for the built in hedges
+// and filler words
+func defaultHedgeEntries() []*rules.ListEntry {
+	var entries []*rules.ListEntry
+
+	for _, phrase := range hedgePhrases {
+		entries = append(entries, rules.NewListEntry(phrase, nil, hedgeMessage))
+	}
+
+	for _, phrase := range fillerPhrases {
+		entries = append(entries, rules.NewListEntry(phrase, nil, fillerMessage))
+	}
+
+	return entries
+}
+
+// UseHedgeProcessor is a convenience variable for referencing a ListProcessor
+// which finds hedges and filler words
+var UseHedgeProcessor = ListProcessor{
+	Label:   "hedge",
+	Message: "'%s' weakens the statement.",
+	Entries: defaultHedgeEntries(),
+}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "hedge",
+		Name:        "Hedges and Filler",
+		Legend:      "Hedges & Filler",
+		Description: "Hedges such as \"I think\" or \"sort of\" make writing sound unsure, and filler such as \"just\" or \"needless to say\" adds words without meaning. Cutting them makes a statement stronger.",
+		Message:     UseHedgeProcessor.Message,
+		Category:    "style",
+		Color:       "225, 110, 160",
+		New: func(r rules.Rule) rules.Processor {
+			return ListProcessor{
+				Label:   "hedge",
+				Message: r.Message,
+				// A message set in the profile replaces the ones for hedges
+				// and filler
+				Override: r.MessageSet,
+				Entries:  r.Entries(UseHedgeProcessor.Entries),
+			}
+		},
+	})
+}
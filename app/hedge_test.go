@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestHedgeMessages(t *testing.T) {
+	tests := []struct {
+		message string
+		data    string
+		want    []string
+	}{
+		{"", "I think it just works.", []string{
+			"'I think' hedges the statement. Say it with confidence or leave it out.",
+			"'just' is filler which adds words without meaning. Try leaving it out.",
+		}},
+		{"Avoid '%s'.", "I think it just works.", []string{"Avoid 'I think'.", "Avoid 'just'."}},
+		{"", "It is very quite extremely really good.", []string{
+			"'very' is filler which adds words without meaning. Try leaving it out.",
+			"'really' is filler which adds words without meaning. Try leaving it out.",
+		}},
+		{"Avoid '%s'.", "It actually works.", []string{"Avoid 'actually'."}},
+	}
+
+	for _, test := range tests {
+		c := profileProcessor(t, "hedge", test.message).Process(rules.NewChunk(0, test.data))
+
+		var got []string
+		for _, m := range c.Matches {
+			got = append(got, m.Message)
+		}
+
+		if len(got) != len(test.want) {
+			t.Errorf("%q with message %q gave %q, want %q", test.data, test.message, got, test.want)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%q with message %q gave %q, want %q", test.data, test.message, got, test.want)
+				break
+			}
+		}
+	}
+}
@@ -3,9 +3,10 @@ package main
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-const SentenceEnders = ".!?"
+const SentenceEnders = ".!?…"
 
 // IsAlpha checks if a current rune is a letter
 func IsAlpha(r rune) bool {
@@ -22,7 +23,88 @@ func IsAlphaNumeric(r rune) bool {
 	return IsAlpha(r) || unicode.IsNumber(r)
 }
 
-// IsEndOfSentence checks if we have punctuation to end a sentence
+// IsEndOfSentence checks if we have punctuation which can end a sentence. The
+// Segmenter decides if it really does.
 func IsEndOfSentence(r rune) bool {
 	return strings.ContainsRune(SentenceEnders, r)
 }
+
+// Words splits text into its words made up of letters, numbers and
+// apostrophes
+func Words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !IsAlphaNumeric(r) && r != '\''
+	})
+}
+
+// FormatMessage fills in each %s of a match message with the value. Messages
+// can be set in the config so they aren't treated as format strings.
+func FormatMessage(msg string, value string) string {
+	return strings.Replace(msg, "%s", value, -1)
+}
+
+// overlapsAny checks if a span overlaps any of the spans already taken
+func overlapsAny(taken [][]int, span []int) bool {
+	for _, t := range taken {
+		if span[0] < t[1] && t[0] < span[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LineCounter finds the line and column of offsets in a text. Asking for
+// offsets in increasing order only scans the text once.
+type LineCounter struct {
+	text   string
+	offset int
+	char   int
+	line   int
+	col    int
+}
+
+// NewLineCounter is a convenience function to build a LineCounter for a text
+func NewLineCounter(text string) *LineCounter {
+	return &LineCounter{text: text, line: 1, col: 1}
+}
+
+// LineCol converts a byte offset in the text into a line and column, both
+// starting at 1. The column counts characters rather than bytes.
+func (l *LineCounter) LineCol(offset int) (int, int) {
+	l.seek(offset)
+
+	return l.line, l.col
+}
+
+// Char converts a byte offset in the text into the number of characters
+// before it
+func (l *LineCounter) Char(offset int) int {
+	l.seek(offset)
+
+	return l.char
+}
+
+// seek moves the counter to the byte offset, starting over when it is
+// behind the last one
+func (l *LineCounter) seek(offset int) {
+	if offset > len(l.text) {
+		offset = len(l.text)
+	}
+
+	if offset < l.offset {
+		l.offset, l.char, l.line, l.col = 0, 0, 1, 1
+	}
+
+	for l.offset < offset {
+		r, size := utf8.DecodeRuneInString(l.text[l.offset:])
+		if r == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.offset += size
+		l.char++
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestLineCol(t *testing.T) {
+	tests := []struct {
+		text      string
+		offset    int
+		line, col int
+	}{
+		{"hello\nworld", 0, 1, 1},
+		{"hello\nworld", 6, 2, 1},
+		{"héllo\nwörld", 4, 1, 4},
+		{"héllo\nwörld", 10, 2, 3},
+		{"日本\n語", 7, 2, 1},
+		{"a\xff\nb", 2, 1, 3},
+		{"a\xff\nb", 3, 2, 1},
+		{"short", 100, 1, 6},
+	}
+
+	for _, test := range tests {
+		line, col := NewLineCounter(test.text).LineCol(test.offset)
+		if line != test.line || col != test.col {
+			t.Errorf("LineCol(%q, %d) = %d:%d, want %d:%d", test.text, test.offset, line, col, test.line, test.col)
+		}
+	}
+}
+
+func TestLineColGoingBack(t *testing.T) {
+	counter := NewLineCounter("one\ntwö\nthree")
+
+	offsets := []struct {
+		offset    int
+		line, col int
+	}{
+		{9, 3, 1},
+		{5, 2, 2},
+		{13, 3, 5},
+	}
+
+	for _, test := range offsets {
+		line, col := counter.LineCol(test.offset)
+		if line != test.line || col != test.col {
+			t.Errorf("LineCol(%d) = %d:%d, want %d:%d", test.offset, line, col, test.line, test.col)
+		}
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	tests := []struct {
+		msg   string
+		value string
+		want  string
+	}{
+		{"This is a %s sentence.", "long", "This is a long sentence."},
+		{"Keep sentences short.", "long", "Keep sentences short."},
+		{"100% of readers skip %s sentences.", "long", "100% of readers skip long sentences."},
+		{"'%s' and '%s' again.", "so", "'so' and 'so' again."},
+		{"%d %s", "x", "%d x"},
+	}
+
+	for _, test := range tests {
+		if got := FormatMessage(test.msg, test.value); got != test.want {
+			t.Errorf("FormatMessage(%q, %q) = %q, want %q", test.msg, test.value, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"write-better/rules"
+)
+
+// htmlSkipped are the elements whose contents are never shown as prose
+var htmlSkipped = map[string]bool{
+	"head":     true,
+	"script":   true,
+	"style":    true,
+	"code":     true,
+	"pre":      true,
+	"noscript": true,
+	"template": true,
+	"textarea": true,
+	"svg":      true,
+	"math":     true,
+}
+
+// htmlBlocks are the elements which start a new paragraph of text
+var htmlBlocks = map[string]bool{
+	"address":    true,
+	"article":    true,
+	"aside":      true,
+	"blockquote": true,
+	"body":       true,
+	"caption":    true,
+	"dd":         true,
+	"div":        true,
+	"dl":         true,
+	"dt":         true,
+	"figcaption": true,
+	"figure":     true,
+	"footer":     true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"header":     true,
+	"hr":         true,
+	"li":         true,
+	"main":       true,
+	"nav":        true,
+	"ol":         true,
+	"p":          true,
+	"section":    true,
+	"table":      true,
+	"td":         true,
+	"th":         true,
+	"tr":         true,
+	"ul":         true,
+}
+
+// htmlEntity finds a character reference at the start of raw HTML text
+var htmlEntity = regexp.MustCompile(`^&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);?`)
+
+// HTMLBlock is a block element of an HTML document which text came from
+type HTMLBlock struct {
+	// Tag is the name of the element such as "p" or "h2"
+	Tag string
+	// Start is the offset in the source where the block's text begins
+	Start int
+	// End is the offset in the source where the block's text ends
+	End int
+}
+
+// HTMLChunker is a Chunker instance which splits the visible text of an HTML
+// document by sentences. Each block element such as a paragraph, heading or
+// list item becomes its own paragraph while scripts, styles and code are
+// skipped. The chunks keep the positions of their text in the original HTML
+// and the element they came from.
+type HTMLChunker struct {
+	// Input is the HTML to be chunked
+	Input string
+	// Segmenter finds where sentences end, defaulting to the default
+	// language's when nil
+	Segmenter *Segmenter
+}
+
+// NewHTMLChunker is a convenience function to give us a HTMLChunker object
+func NewHTMLChunker(input string) *HTMLChunker {
+	return &HTMLChunker{Input: input}
+}
+
+// Chunk pulls the visible text out of the HTML and splits it by sentences
+func (c HTMLChunker) Chunk() (rules.Chunks, Summary, error) {
+	text, positions, blocks := ExtractHTML(c.Input)
+
+	chunks, summary, err := SentenceChunker{Input: text, Positions: positions, Segmenter: c.Segmenter}.Chunk()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Record which element each chunk came from
+	for _, chunk := range chunks {
+		for _, block := range blocks {
+			if chunk.Offset >= block.Start && chunk.Offset < block.End {
+				chunk.Source = block.Tag
+				break
+			}
+		}
+	}
+
+	return chunks, summary, nil
+}
+
+// ExtractHTML pulls the visible text out of an HTML document with one block
+// element per line. Whitespace is collapsed the same way a browser does and
+// character references are decoded. The source offset of each byte in the
+// result is returned along with the blocks the text came from.
+func ExtractHTML(input string) (string, []int, []HTMLBlock) {
+	var out SourceText
+	var blocks []HTMLBlock
+	var stack []string
+
+	skipped := 0
+	blockStart := -1
+	blockEnd := 0
+	pendingSpace := false
+
+	// Blocks end with a newline placed at the end of their last text
+	endBlock := func() {
+		if blockStart >= 0 {
+			out.WriteAt("\n", blockEnd)
+
+			tag := "body"
+			if len(stack) > 0 {
+				tag = stack[len(stack)-1]
+			}
+			blocks = append(blocks, HTMLBlock{Tag: tag, Start: blockStart, End: blockEnd})
+		}
+
+		blockStart = -1
+		pendingSpace = false
+	}
+
+	z := html.NewTokenizer(strings.NewReader(input))
+
+	offset := 0
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		raw := string(z.Raw())
+		start := offset
+		offset += len(raw)
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+
+			if htmlSkipped[tag] && tt != html.SelfClosingTagToken {
+				if tt == html.StartTagToken {
+					skipped++
+				} else if skipped > 0 {
+					skipped--
+				}
+				continue
+			}
+
+			if tag == "br" {
+				pendingSpace = blockStart >= 0
+				continue
+			}
+
+			if !htmlBlocks[tag] || skipped > 0 {
+				continue
+			}
+
+			endBlock()
+
+			if tt == html.StartTagToken {
+				// Paragraphs and list items don't need to be closed
+				if len(stack) > 0 && stack[len(stack)-1] == tag && (tag == "p" || tag == "li") {
+					stack = stack[:len(stack)-1]
+				}
+				stack = append(stack, tag)
+			} else if tt == html.EndTagToken {
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == tag {
+						stack = stack[:i]
+						break
+					}
+				}
+			}
+		case html.TextToken:
+			if skipped > 0 {
+				continue
+			}
+
+			for i := 0; i < len(raw); {
+				if strings.IndexByte(" \t\n\r\f", raw[i]) >= 0 {
+					pendingSpace = blockStart >= 0
+					i++
+					continue
+				}
+
+				if blockStart < 0 {
+					blockStart = start + i
+				} else if pendingSpace {
+					out.WriteAt(" ", start+i-1)
+				}
+				pendingSpace = false
+
+				if entity := htmlEntity.FindString(raw[i:]); entity != "" {
+					out.WriteSpan(html.UnescapeString(entity), start+i, start+i+len(entity))
+					i += len(entity)
+				} else {
+					out.Write(raw[i:i+1], start+i)
+					i++
+				}
+
+				blockEnd = start + i
+			}
+		}
+	}
+
+	endBlock()
+
+	return out.String(), out.Positions, blocks
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestExtractHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"skipped elements", "<p>Keep this.</p><script>var x = 1;</script><style>p { color: red; }</style><pre>code block</pre><p>Run <code>go test</code> now.</p>", "Keep this.\nRun now.\n"},
+		{"head", "<html><head><title>Title</title></head><body><p>Body text.</p></body></html>", "Body text.\n"},
+		{"blocks", "<h1>Title</h1><p>First</p><p>Second</p><ul><li>one<li>two</ul>", "Title\nFirst\nSecond\none\ntwo\n"},
+		{"inline elements", "<p>Some <b>bold</b> and <a href=\"x\">linked</a> text.</p>", "Some bold and linked text.\n"},
+		{"whitespace", "<p>  Spread\n   over\tlines<br>and broken.  </p>", "Spread over lines and broken.\n"},
+		{"entities", "<p>Fish &amp; chips &#8212; caf&eacute; &lt;3</p>", "Fish & chips — café <3\n"},
+	}
+
+	for _, test := range tests {
+		got, positions, _ := ExtractHTML(test.input)
+		if got != test.want {
+			t.Errorf("%s: ExtractHTML() = %q, want %q", test.name, got, test.want)
+		}
+		if len(positions) != len(got) {
+			t.Errorf("%s: %d positions for %d bytes", test.name, len(positions), len(got))
+		}
+	}
+}
+
+func TestExtractHTMLEntityOffsets(t *testing.T) {
+	input := "<p>Le caf&eacute; AT&amp;T</p>"
+
+	chunks, _, err := HTMLChunker{Input: input}.Chunk()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("got %d chunks, want 1", len(chunks))
+	}
+
+	tests := []struct {
+		word   string
+		source string
+	}{
+		{"café", "caf&eacute;"},
+		{"AT&T", "AT&amp;T"},
+	}
+
+	c := chunks[0]
+	for _, test := range tests {
+		first := strings.Index(c.Data, test.word)
+		last := first + len(test.word)
+
+		if got := input[c.SourceOffset(first):c.SourceEnd(last)]; got != test.source {
+			t.Errorf("%q maps to %q in the source, want %q", test.word, got, test.source)
+		}
+	}
+}
+
+func TestHTMLBlocksBreakSentences(t *testing.T) {
+	chunks, _, err := HTMLChunker{Input: "<h2>No full stop</h2><p>First sentence. Second one</p><li>Item</li>"}.Chunk()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(rules.ByChunk(chunks))
+
+	type chunk struct {
+		Data   string
+		Para   bool
+		Source string
+	}
+
+	var got []chunk
+	for _, c := range chunks {
+		got = append(got, chunk{c.Data, c.IsNewParagraph, c.Source})
+	}
+
+	want := []chunk{
+		{"No full stop", true, "h2"},
+		{"First sentence.", true, "p"},
+		{" Second one", false, "p"},
+		{"Item", true, "li"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks are %v, want %v", got, want)
+	}
+}
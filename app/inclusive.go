@@ -0,0 +1,124 @@
+package main
+
+import "write-better/rules"
+
+// inclusiveTerms are the built in terms for the inclusive language processor
+// grouped by the message given with them. Each term is written like a line of
+// a phrase list file.
+var inclusiveTerms = []struct {
+	Message string
+	Terms   []string
+}{
+	{"'%s' is gendered. Consider a neutral term.", []string{
+		"chairman | chair, chairperson",
+		"chairmen | chairs, chairpersons",
+		"businessman | businessperson",
+		"businessmen | businesspeople",
+		"fireman | firefighter",
+		"firemen | firefighters",
+		"policeman | police officer",
+		"policemen | police officers",
+		"mailman | mail carrier",
+		"postman | mail carrier",
+		"salesman | salesperson",
+		"salesmen | salespeople",
+		"spokesman | spokesperson",
+		"spokesmen | spokespeople",
+		"cameraman | camera operator",
+		"foreman | supervisor",
+		"middleman | intermediary",
+		"layman | layperson",
+		"laymen | laypeople",
+		"congressman | member of Congress",
+		"stewardess | flight attendant",
+		"waitress | server",
+		"manpower | workforce, staff",
+		"man-hours | person-hours, work hours",
+		"mankind | humankind, humanity",
+		"man-made | artificial, synthetic",
+		"you guys | you all, everyone",
+	}},
+	{"'%s' can be hurtful to people with disabilities.", []string{
+		"crazy | wild, surprising",
+		"insane | unbelievable, wild",
+		"lame | disappointing, weak",
+		"crippled | impaired, hobbled",
+		"handicapped | disabled",
+		"wheelchair-bound | wheelchair user",
+		"tone-deaf | out of touch",
+		"turn a blind eye | ignore",
+		"falls on deaf ears | is ignored",
+		"blind spot | gap",
+		"psycho",
+		"lunatic",
+	}},
+	{"'%s' has non-inclusive connotations.", []string{
+		"whitelist | allowlist",
+		"whitelists | allowlists",
+		"whitelisted | allowlisted",
+		"whitelisting | allowlisting",
+		"blacklist | blocklist, denylist",
+		"blacklists | blocklists, denylists",
+		"blacklisted | blocklisted, denylisted",
+		"blacklisting | blocklisting, denylisting",
+		"master/slave | primary/replica, leader/follower",
+		"slave | replica, follower, secondary",
+		"slaves | replicas, followers",
+		"master branch | main branch",
+		"master node | primary node",
+		"sanity check | quick check, confidence check",
+		"sanity test | smoke test",
+		"dummy value | placeholder value",
+		"grandfathered | legacy",
+		"grandfather clause | legacy clause",
+		"man-in-the-middle | on-path",
+	}},
+}
+
+// defaultInclusiveEntries builds the phrase list entries for the built in
+// inclusive language terms
+func defaultInclusiveEntries() []*rules.ListEntry {
+	var entries []*rules.ListEntry
+
+	for _, group := range inclusiveTerms {
+		// The built in terms are known to parse
+		parsed, _ := rules.ParsePhrases(group.Terms)
+		for _, entry := range parsed {
+			entry.Message = group.Message
+		}
+
+		entries = append(entries, parsed...)
+	}
+
+	return entries
+}
+
+// UseInclusiveProcessor is a convenience variable for referencing a
+// ListProcessor which finds non-inclusive language
+var UseInclusiveProcessor = ListProcessor{
+	Label:   "inclusive",
+	Message: "'%s' may not be inclusive. Consider another term.",
+	Entries: defaultInclusiveEntries(),
+}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "inclusive",
+		Name:        "Inclusive Language",
+		Legend:      "Non-Inclusive Terms",
+		Description: "Gendered job titles, ableist terms and technical terms like \"whitelist\" or \"master/slave\" can exclude or hurt readers. There is almost always a neutral term which says the same thing.",
+		Message:     UseInclusiveProcessor.Message,
+		Category:    "inclusivity",
+		Color:       "40, 150, 200",
+		New: func(r rules.Rule) rules.Processor {
+			return ListProcessor{
+				Label:   "inclusive",
+				Message: r.Message,
+				// A message set in the profile replaces the ones for each
+				// group of terms
+				Override: r.MessageSet,
+				Entries:  r.Entries(UseInclusiveProcessor.Entries),
+			}
+		},
+	})
+}
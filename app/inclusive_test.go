@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestInclusiveMessage(t *testing.T) {
+	c := profileProcessor(t, "inclusive", "").Process(rules.NewChunk(0, "Add it to the whitelist."))
+	if len(c.Matches) != 1 || c.Matches[0].Message == UseInclusiveProcessor.Message {
+		t.Errorf("default message gave %v, want the term's own message", c.Matches)
+	}
+
+	c = profileProcessor(t, "inclusive", "Avoid '%s'.").Process(rules.NewChunk(0, "Add it to the whitelist."))
+	if len(c.Matches) != 1 || c.Matches[0].Message != "Avoid 'whitelist'." {
+		t.Errorf("profile message gave %v, want %q", c.Matches, "Avoid 'whitelist'.")
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"write-better/rules"
+)
+
+// DefaultListMessage is the message given for a list match where %s is
+// replaced with the phrase that was found
+const DefaultListMessage = "Avoid using '%s'."
+
+// DefaultListColor is the highlight color for lists without one set
+const DefaultListColor = "120, 120, 120"
+
+// ListProcessor processes phrases from user supplied phrase lists
+type ListProcessor struct {
+	// Label is the type of processor given to each match
+	Label string
+	// Message is the message given with each match where %s is replaced with
+	// the phrase that was found
+	Message string
+	// Override gives Message for every entry, even those with their own
+	// message, such as when a profile replaces the message
+	Override bool
+	// Entries are the phrases to look for
+	Entries []*rules.ListEntry
+}
+
+// Process handles the processing for phrase list matches
+func (p ListProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	var taken [][]int
+
+	normalized := NormalizeForMatching(c.Data)
+
+	for _, entry := range p.Entries {
+		for _, found := range entry.FindAll(normalized) {
+			// Entries earlier in the list win when phrases overlap such as
+			// "it seems that" and "it seems"
+			indices := RuneIndices(normalized, found)
+			if overlapsAny(taken, indices) {
+				continue
+			}
+			taken = append(taken, indices)
+
+			match := RuneSlice(c.Data, indices[0], indices[1])
+
+			// A phrase that is already written exactly like one of its
+			// replacements is fine (e.g. checking capitalization)
+			if containsString(entry.Replacements, match) {
+				continue
+			}
+
+			m := rules.NewMatch(match, p.Label, indices, p.message(entry))
+			for _, replacement := range entry.Replacements {
+				m.Suggestions = append(m.Suggestions, MatchCase(match, replacement))
+			}
+
+			c.Matches = append(c.Matches, m)
+			c.Score += 1
+		}
+	}
+
+	return c
+}
+
+// message builds the message for a phrase list entry
+func (p ListProcessor) message(entry *rules.ListEntry) string {
+	msg := p.Message
+	if entry.Message != "" && !p.Override {
+		msg = entry.Message
+	}
+
+	return FormatMessage(msg, entry.Phrase)
+}
+
+// ListConfig holds the settings for a phrase list in the config file
+type ListConfig struct {
+	// Name is the title shown when describing the list
+	Name string `toml:"name"`
+	// Description explains what the list looks for
+	Description string `toml:"description"`
+	// Message is the default message given with each match
+	Message string `toml:"message"`
+	// Category groups similar processors together
+	Category string `toml:"category"`
+	// Color is the "r, g, b" value used to highlight matches
+	Color string `toml:"color"`
+	// Files are the phrase list files relative to the config file
+	Files []string `toml:"files"`
+}
+
+// LoadList loads the phrase list files and describes a ListProcessor for them
+// under the given label, ready to be registered. Relative files are found
+// from the directory dir.
+func LoadList(label string, list ListConfig, dir string) (rules.ProcessorInfo, error) {
+	var entries []*rules.ListEntry
+
+	if _, ok := rules.LookupProcessor(label); ok {
+		return rules.ProcessorInfo{}, fmt.Errorf("list %q: label is already used by another processor", label)
+	}
+
+	for _, file := range list.Files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+
+		loaded, err := rules.LoadPhraseList(file)
+		if err != nil {
+			return rules.ProcessorInfo{}, fmt.Errorf("list %q: %s", label, err)
+		}
+
+		entries = append(entries, loaded...)
+	}
+
+	info := rules.ProcessorInfo{
+		Label:       label,
+		Name:        list.Name,
+		Legend:      list.Name,
+		Description: list.Description,
+		Message:     list.Message,
+		Category:    list.Category,
+		Color:       list.Color,
+		New: func(r rules.Rule) rules.Processor {
+			return ListProcessor{Label: label, Message: r.Message, Entries: entries}
+		},
+	}
+
+	if info.Name == "" {
+		info.Name, info.Legend = label, label
+	}
+	if info.Message == "" {
+		info.Message = DefaultListMessage
+	}
+	if info.Category == "" {
+		info.Category = "style"
+	}
+	if info.Color == "" {
+		info.Color = DefaultListColor
+	}
+
+	return info, nil
+}
+
+// containsString checks if a string is in a list of strings
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
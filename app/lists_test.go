@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestListProcessor(t *testing.T) {
+	entries, err := rules.ParsePhrases([]string{
+		"café | coffee shop",
+		"Github | GitHub | Use the product name.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := ListProcessor{Label: "names", Message: DefaultListMessage, Entries: entries}
+	c := p.Process(rules.NewChunk(0, "We met at the Café near GitHub and Github."))
+
+	type result struct {
+		Match       string
+		Indices     []int
+		Message     string
+		Suggestions []string
+	}
+
+	var got []result
+	for _, m := range c.Matches {
+		got = append(got, result{m.Match, m.Indices, m.Message, m.Suggestions})
+	}
+
+	want := []result{
+		{"Café", []int{14, 18}, "Avoid using 'café'.", []string{"Coffee shop"}},
+		{"Github", []int{35, 41}, "Use the product name.", []string{"GitHub"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Process() = %v, want %v", got, want)
+	}
+}
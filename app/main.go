@@ -1,41 +1,43 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 )
 
-// processors refer to the list of active processors we are running in the
-// application at any given time
-var processors Processor = ActiveProcessors{
-	UseSentenceLengthProcessor,
-	UsePassiveVoiceProcessor,
-	UseWeaselWordProcessor,
-	UseTooWordyProcessor,
-	UseAdverbProcessor,
-	UseClicheProcessor,
-	UseLexicalIllusionProcessor,
-	UseStartsWithProcessor,
-	// This needs to be the last one since it manipuates the string itself
-	UseHTMLProcessor,
-}
+// appConfig refers to the style profiles loaded at startup
+var appConfig = DefaultConfig()
+
+// appSessions refers to the analysis sessions for each submitted text
+var appSessions = NewSessionStore()
 
-// appText refers to the text that has been submitted
-var appText string
+func main() {
+	// Run as a linter when asked instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:]))
+	}
 
-// appResult refers to the processing results
-var appResult Chunks
+	configPath := flag.String("config", "", "path to a TOML file with style profiles")
+	flag.Parse()
 
-// appSummary refers to overal text data summary
-var appSummary map[string]int
+	if *configPath != "" {
+		config, err := LoadConfig(*configPath)
+		if err != nil {
+			log.Fatal("LoadConfig:", err)
+		}
+		appConfig = config
+	}
 
-func main() {
 	http.Handle("/", &templateHandler{filename: "index.html"})
 	http.HandleFunc("/upload", uploaderHandler)
 	http.HandleFunc("/paste", pasteHandler)
-	http.HandleFunc("/process", processorsHandler)
-	http.HandleFunc("/results", resultHandler)
+	http.HandleFunc("/process/", processorsHandler)
+	http.HandleFunc("/results/", resultHandler)
+	http.HandleFunc("/api/v1/analyze", apiAnalyzeHandler)
+	http.HandleFunc("/api/v1/fix", apiFixHandler)
 
 	fmt.Println("App server running on :17644")
 
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"write-better/rules"
+)
+
+var (
+	mdFence         = regexp.MustCompile("^(`{3,}|~{3,})")
+	mdHeading       = regexp.MustCompile(`^#{1,6}(\s+|$)`)
+	mdSetext        = regexp.MustCompile(`^(=+|-+)\s*$`)
+	mdThematicBreak = regexp.MustCompile(`^([-*_]\s*){3,}$`)
+	mdReference     = regexp.MustCompile(`^\[[^\]]+\]:\s*\S+`)
+	mdQuote         = regexp.MustCompile(`^(>\s?)+`)
+	mdListItem      = regexp.MustCompile(`^([*+-]|\d{1,9}[.)])\s+(\[[ xX]\]\s+)?`)
+	mdInlineTag     = regexp.MustCompile(`^(<[a-zA-Z][a-zA-Z0-9+.-]*:[^\s>]*>|<[^\s@>]+@[^\s>]+>|</?[a-zA-Z][^>]*>)`)
+)
+
+// mdEscapable are the characters which can be escaped with a backslash
+const mdEscapable = "\\`*_{}[]()#+-.!|<>~"
+
+// MarkdownChunker is a Chunker instance which splits Markdown by sentences.
+// Code blocks and inline code are skipped, link URLs and formatting are
+// stripped, and headings and list items become their own paragraphs. The
+// chunks keep the positions of their text in the original Markdown.
+type MarkdownChunker struct {
+	// Input is the Markdown to be chunked
+	Input string
+	// Segmenter finds where sentences end, defaulting to the default
+	// language's when nil
+	Segmenter *Segmenter
+}
+
+// NewMarkdownChunker is a convenience function to give us a MarkdownChunker object
+func NewMarkdownChunker(input string) *MarkdownChunker {
+	return &MarkdownChunker{Input: input}
+}
+
+// Chunk strips the Markdown down to its prose and splits it by sentences
+func (c MarkdownChunker) Chunk() (rules.Chunks, Summary, error) {
+	text, positions := StripMarkdown(c.Input)
+	return SentenceChunker{Input: text, Positions: positions, Segmenter: c.Segmenter}.Chunk()
+}
+
+// StripMarkdown pulls the prose out of Markdown with one paragraph, heading
+// or list item per line. Lines wrapped within a paragraph are joined. The
+// source offset of each byte in the result is returned along with it.
+func StripMarkdown(input string) (string, []int) {
+	var out SourceText
+	var fence string
+
+	inPara := false
+	lineEnd := 0
+
+	// Paragraphs end with a newline placed at the end of their last line
+	endPara := func() {
+		if inPara {
+			out.WriteAt("\n", lineEnd)
+			inPara = false
+		}
+	}
+
+	offset := 0
+	for _, raw := range strings.SplitAfter(input, "\n") {
+		start := offset
+		offset += len(raw)
+
+		line := strings.TrimRight(raw, "\r\n")
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		contentStart := start + indent
+
+		// Skip everything inside a fenced code block
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(trimmed) == "":
+			endPara()
+		case indent < 4 && mdFence.MatchString(trimmed):
+			endPara()
+			fence = mdFence.FindString(trimmed)
+		case indent >= 4 && !inPara:
+			// Indented code block
+		case mdHeading.MatchString(trimmed):
+			endPara()
+			marker := mdHeading.FindString(trimmed)
+			text := strings.TrimRight(trimmed[len(marker):], " \t#")
+			writeMarkdownInline(&out, text, contentStart+len(marker))
+			inPara = true
+			lineEnd = start + len(line)
+			endPara()
+		case inPara && mdSetext.MatchString(trimmed):
+			// The paragraph before this line was a heading
+			endPara()
+		case mdThematicBreak.MatchString(trimmed), mdReference.MatchString(trimmed):
+			endPara()
+		default:
+			if quote := mdQuote.FindString(trimmed); quote != "" {
+				trimmed = trimmed[len(quote):]
+				contentStart += len(quote)
+
+				if strings.TrimSpace(trimmed) == "" {
+					endPara()
+					break
+				}
+			}
+
+			if item := mdListItem.FindString(trimmed); item != "" {
+				endPara()
+				trimmed = trimmed[len(item):]
+				contentStart += len(item)
+			}
+
+			// Wrapped lines are joined into one line for the paragraph
+			if inPara {
+				out.WriteAt(" ", lineEnd)
+			}
+
+			writeMarkdownInline(&out, strings.TrimRight(trimmed, " \t"), contentStart)
+			inPara = true
+		}
+
+		lineEnd = start + len(line)
+	}
+
+	endPara()
+
+	return out.String(), out.Positions
+}
+
+// writeMarkdownInline writes a line of Markdown without its inline code,
+// images, link URLs, HTML tags and emphasis markers
+func writeMarkdownInline(out *SourceText, s string, offset int) {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(mdEscapable, s[i+1]) >= 0:
+			out.Write(s[i+1:i+2], offset+i+1)
+			i += 2
+		case c == '`':
+			ticks := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			closing := strings.Index(s[i+ticks:], s[i:i+ticks])
+			if closing < 0 {
+				out.Write(s[i:i+ticks], offset+i)
+				i += ticks
+			} else {
+				i += ticks + closing + ticks
+			}
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if _, end, ok := markdownLink(s, i+1); ok {
+				i = end
+			} else {
+				out.Write(s[i:i+1], offset+i)
+				i++
+			}
+		case c == '[':
+			if textEnd, end, ok := markdownLink(s, i); ok {
+				writeMarkdownInline(out, s[i+1:textEnd], offset+i+1)
+				i = end
+			} else {
+				out.Write(s[i:i+1], offset+i)
+				i++
+			}
+		case c == '<' && mdInlineTag.MatchString(s[i:]):
+			i += len(mdInlineTag.FindString(s[i:]))
+		case (c == '*' || c == '_' || c == '~') && isEmphasisMarker(s, i):
+			i++
+		default:
+			out.Write(s[i:i+1], offset+i)
+			i++
+		}
+	}
+}
+
+// markdownLink finds the end of a link's text and the end of the whole link
+// for a link starting at the [ found at i. Both inline links [text](url) and
+// reference links [text][ref] are handled.
+func markdownLink(s string, i int) (int, int, bool) {
+	textEnd := matchingBracket(s, i, '[', ']')
+	if textEnd < 0 || textEnd+1 >= len(s) {
+		return 0, 0, false
+	}
+
+	switch s[textEnd+1] {
+	case '(':
+		if end := matchingBracket(s, textEnd+1, '(', ')'); end >= 0 {
+			return textEnd, end + 1, true
+		}
+	case '[':
+		if end := matchingBracket(s, textEnd+1, '[', ']'); end >= 0 {
+			return textEnd, end + 1, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// matchingBracket finds the closing bracket for the opening one at i allowing
+// for nested brackets. It returns -1 when there isn't one.
+func matchingBracket(s string, i int, open byte, close byte) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+
+	return -1
+}
+
+// isEmphasisMarker checks if the *, _ or ~ at i is formatting rather than
+// part of the text. Markers must touch a word and underscores inside a word
+// such as snake_case are left alone.
+func isEmphasisMarker(s string, i int) bool {
+	var prev, next byte = ' ', ' '
+	if i > 0 {
+		prev = s[i-1]
+	}
+	if i+1 < len(s) {
+		next = s[i+1]
+	}
+
+	if prev == ' ' && next == ' ' {
+		return false
+	}
+
+	if s[i] == '_' && isWordByte(prev) && isWordByte(next) {
+		return false
+	}
+
+	return true
+}
+
+// isWordByte checks if a byte is an ASCII letter or number
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
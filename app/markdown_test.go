@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestStripMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"fenced code", "Before.\n\n```go\nx := 1\n```\n\nAfter.\n", "Before.\nAfter.\n"},
+		{"tilde fence", "~~~\ncode\n~~~\nText.\n", "Text.\n"},
+		{"indented code", "Text.\n\n    code here\n", "Text.\n"},
+		{"inline code", "Run `go test` now.\n", "Run  now.\n"},
+		{"link", "See [the docs](https://example.com/docs) first.\n", "See the docs first.\n"},
+		{"reference link", "See [the docs][docs].\n\n[docs]: https://example.com\n", "See the docs.\n"},
+		{"image", "An ![logo](logo.png) here.\n", "An  here.\n"},
+		{"autolink", "Mail <me@example.com> or <https://example.com>.\n", "Mail  or .\n"},
+		{"headings", "# Title\n\n## Sub heading ##\nText.\n", "Title\nSub heading\nText.\n"},
+		{"setext heading", "Title\n=====\nText.\n", "Title\nText.\n"},
+		{"list markers", "- one\n* two\n1. three\n- [x] done\n", "one\ntwo\nthree\ndone\n"},
+		{"quote", "> Quoted\n> text.\n", "Quoted text.\n"},
+		{"emphasis", "Some *very* __bold__ snake_case.\n", "Some very bold snake_case.\n"},
+		{"wrapped lines", "One line\nwraps here.\n", "One line wraps here.\n"},
+		{"escapes", "Not \\*emphasis\\*.\n", "Not *emphasis*.\n"},
+	}
+
+	for _, test := range tests {
+		got, positions := StripMarkdown(test.input)
+		if got != test.want {
+			t.Errorf("%s: StripMarkdown(%q) = %q, want %q", test.name, test.input, got, test.want)
+		}
+
+		if len(positions) != len(got) {
+			t.Errorf("%s: %d positions for %d bytes", test.name, len(positions), len(got))
+			continue
+		}
+
+		// Every byte other than the joins between lines comes from the
+		// same byte in the source
+		for i := range got {
+			if got[i] != '\n' && got[i] != ' ' && test.input[positions[i]] != got[i] {
+				t.Errorf("%s: byte %d %q maps to %q", test.name, i, got[i], test.input[positions[i]])
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"write-better/rules"
+)
+
+// Verb forms used when conjugating
+const (
+	formBase = iota
+	formThird
+	formPast
+	formGerund
+	formParticiple
+)
+
+// irregularVerbs are the forms of verbs which don't follow the usual rules in
+// the order base, third person, past, gerund and past participle
+var irregularVerbs = map[string][]string{
+	"choose": {"choose", "chooses", "chose", "choosing", "chosen"},
+	"come":   {"come", "comes", "came", "coming", "come"},
+	"do":     {"do", "does", "did", "doing", "done"},
+	"give":   {"give", "gives", "gave", "giving", "given"},
+	"have":   {"have", "has", "had", "having", "had"},
+	"hold":   {"hold", "holds", "held", "holding", "held"},
+	"make":   {"make", "makes", "made", "making", "made"},
+	"meet":   {"meet", "meets", "met", "meeting", "met"},
+	"pay":    {"pay", "pays", "paid", "paying", "paid"},
+	"refer":  {"refer", "refers", "referred", "referring", "referred"},
+	"take":   {"take", "takes", "took", "taking", "taken"},
+}
+
+// Conjugate gives a form of a verb such as formPast. Regular verbs are built
+// from their spelling and irregular ones are looked up.
+func Conjugate(verb string, form int) string {
+	if forms, ok := irregularVerbs[verb]; ok {
+		return forms[form]
+	}
+
+	consonantY := strings.HasSuffix(verb, "y") && len(verb) > 1 && !strings.ContainsRune("aeiou", rune(verb[len(verb)-2]))
+
+	switch form {
+	case formThird:
+		switch {
+		case consonantY:
+			return verb[:len(verb)-1] + "ies"
+		case strings.HasSuffix(verb, "s"), strings.HasSuffix(verb, "sh"), strings.HasSuffix(verb, "ch"), strings.HasSuffix(verb, "x"), strings.HasSuffix(verb, "z"):
+			return verb + "es"
+		}
+		return verb + "s"
+	case formPast, formParticiple:
+		switch {
+		case consonantY:
+			return verb[:len(verb)-1] + "ied"
+		case strings.HasSuffix(verb, "e"):
+			return verb + "d"
+		}
+		return verb + "ed"
+	case formGerund:
+		if strings.HasSuffix(verb, "e") && !strings.HasSuffix(verb, "ee") {
+			return verb[:len(verb)-1] + "ing"
+		}
+		return verb + "ing"
+	}
+
+	return verb
+}
+
+// verbForms lists every form of a verb without duplicates
+func verbForms(verb string) []string {
+	var forms []string
+
+	for form := formBase; form <= formParticiple; form++ {
+		if f := Conjugate(verb, form); !containsString(forms, f) {
+			forms = append(forms, f)
+		}
+	}
+
+	return forms
+}
+
+// lightVerbPhrases are constructions where a weak verb is paired with a noun
+// doing the real work. Each is the light verb, the noun phrase following it
+// and the verb to use instead. A preposition ending the noun phrase is part
+// of the match so the verb replaces it too.
+var lightVerbPhrases = [][]string{
+	{"come", "to a conclusion", "conclude"},
+	{"conduct", "an analysis of", "analyze"},
+	{"conduct", "an investigation", "investigate"},
+	{"conduct", "a review of", "review"},
+	{"do", "an analysis of", "analyze"},
+	{"give", "an explanation", "explain"},
+	{"give", "a description of", "describe"},
+	{"give", "approval", "approve"},
+	{"give", "consideration to", "consider"},
+	{"give", "a presentation", "present"},
+	{"have", "a discussion", "discuss"},
+	{"have", "a meeting", "meet"},
+	{"hold", "a meeting", "meet"},
+	{"make", "an adjustment", "adjust"},
+	{"make", "an assumption", "assume"},
+	{"make", "an attempt", "try"},
+	{"make", "a change", "change"},
+	{"make", "a choice", "choose"},
+	{"make", "a comparison", "compare"},
+	{"make", "a contribution", "contribute"},
+	{"make", "a decision", "decide"},
+	{"make", "an improvement", "improve"},
+	{"make", "a payment", "pay"},
+	{"make", "a recommendation", "recommend"},
+	{"make", "a reference to", "refer to"},
+	{"make", "a request", "request"},
+	{"make", "a suggestion", "suggest"},
+	{"make", "use of", "use"},
+	{"perform", "an analysis of", "analyze"},
+	{"perform", "a test", "test"},
+	{"provide", "assistance", "help"},
+	{"provide", "an explanation", "explain"},
+	{"reach", "an agreement", "agree"},
+	{"reach", "a conclusion", "conclude"},
+	{"reach", "a decision", "decide"},
+	{"take", "action", "act"},
+	{"take", "into consideration", "consider"},
+}
+
+// lightVerbPrepositions are the prepositions which can follow the noun of a
+// light verb construction
+var lightVerbPrepositions = []string{"about", "for", "into", "of", "on", "regarding", "to", "with"}
+
+// followingPreposition finds a preposition straight after a light verb
+// construction such as the "about" in "have a discussion about"
+var followingPreposition = regexp.MustCompile(`(?i)^\s+(` + strings.Join(lightVerbPrepositions, "|") + `)\b`)
+
+// nominalSuffixes are the suffix patterns which turn a verb into a noun. Each
+// verb is turned into its noun by replacing the ending with the suffix where
+// the ending is what the verb must end with.
+var nominalSuffixes = []struct {
+	Ending string
+	Suffix string
+	Verbs  []string
+}{
+	{"ize", "ization", []string{"authorize", "customize", "finalize", "initialize", "maximize", "minimize", "optimize", "organize", "prioritize", "realize", "standardize", "utilize", "visualize"}},
+	{"ate", "ation", []string{"allocate", "automate", "calculate", "communicate", "create", "demonstrate", "estimate", "evaluate", "generate", "illustrate", "indicate", "integrate", "investigate", "migrate", "negotiate", "operate", "participate", "separate", "translate", "validate"}},
+	{"ify", "ification", []string{"clarify", "classify", "identify", "justify", "modify", "notify", "simplify", "specify", "verify"}},
+	{"", "ation", []string{"adapt", "confirm", "consider", "document", "expect", "implement", "present", "represent", "transform"}},
+	{"e", "ation", []string{"explore", "prepare", "declare"}},
+	{"", "ment", []string{"achieve", "adjust", "agree", "align", "announce", "assess", "commit", "deploy", "develop", "enhance", "establish", "improve", "manage", "measure", "move", "replace", "require"}},
+	{"", "ion", []string{"collect", "construct", "correct", "detect", "discuss", "prevent", "protect", "select"}},
+	{"e", "ion", []string{"complete", "execute", "pollute"}},
+	{"", "ance", []string{"accept", "appear", "assist", "perform"}},
+	{"y", "iance", []string{"comply", "rely"}},
+	{"", "ence", []string{"depend", "differ", "exist", "insist", "prefer", "refer"}},
+	{"e", "al", []string{"approve", "arrive", "propose", "refuse", "remove"}},
+}
+
+// irregularNominals are nouns made from verbs without a simple suffix rule
+var irregularNominals = map[string]string{
+	"analysis":     "analyze",
+	"comparison":   "compare",
+	"conclusion":   "conclude",
+	"decision":     "decide",
+	"description":  "describe",
+	"explanation":  "explain",
+	"failure":      "fail",
+	"maintenance":  "maintain",
+	"occurrence":   "occur",
+	"production":   "produce",
+	"reduction":    "reduce",
+	"response":     "respond",
+	"revision":     "revise",
+	"solution":     "solve",
+	"submission":   "submit",
+	"introduction": "introduce",
+}
+
+// nominalVerbs maps each known nominalization to its verb
+var nominalVerbs = func() map[string]string {
+	verbs := make(map[string]string)
+
+	for _, rule := range nominalSuffixes {
+		for _, verb := range rule.Verbs {
+			verbs[strings.TrimSuffix(verb, rule.Ending)+rule.Suffix] = verb
+		}
+	}
+
+	for noun, verb := range irregularNominals {
+		verbs[noun] = verb
+	}
+
+	return verbs
+}()
+
+// lightVerbPattern is a compiled light verb construction
+type lightVerbPattern struct {
+	pattern *regexp.Regexp
+	light   string
+	verb    string
+}
+
+// lightVerbPatterns are the compiled light verb constructions. The article
+// is optional and the noun may be plural so "made decisions" isn't missed.
+var lightVerbPatterns = func() []lightVerbPattern {
+	var patterns []lightVerbPattern
+
+	for _, phrase := range lightVerbPhrases {
+		words := strings.Fields(phrase[1])
+		if words[0] == "a" || words[0] == "an" {
+			words = words[1:]
+		}
+
+		// The plural ending belongs to the noun rather than a preposition
+		// after it
+		noun := strings.Join(words, `\s+`) + `s?`
+		if last := len(words) - 1; last > 0 && containsString(lightVerbPrepositions, words[last]) {
+			noun = strings.Join(words[:last], `\s+`) + `s?\s+` + words[last]
+		}
+
+		expr := `(?i)\b(` + strings.Join(verbForms(phrase[0]), "|") + `)\s+((a|an|the)\s+)?` + noun + `\b`
+		patterns = append(patterns, lightVerbPattern{
+			pattern: regexp.MustCompile(expr),
+			light:   phrase[0],
+			verb:    phrase[2],
+		})
+	}
+
+	return patterns
+}()
+
+// ByLength is a sorting mechanism for sorting strings from longest to shortest
+type ByLength []string
+
+func (s ByLength) Len() int           { return len(s) }
+func (s ByLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByLength) Less(i, j int) bool { return len(s[i]) > len(s[j]) }
+
+// nominalPattern finds a known nominalization followed by "of" as in "the
+// implementation of". The noun is the third group.
+var nominalPattern = func() *regexp.Regexp {
+	var nouns []string
+	for noun := range nominalVerbs {
+		nouns = append(nouns, noun)
+	}
+
+	// Longer nouns go first so the longest one matches
+	sort.Sort(ByLength(nouns))
+
+	return regexp.MustCompile(`(?i)\b((the|a|an)\s+)?(` + strings.Join(nouns, "|") + `)s?\s+of\b`)
+}()
+
+// NominalizationProcessor processes nominalizations, verbs turned into nouns
+// such as "the implementation of" or "make a decision"
+type NominalizationProcessor struct {
+	// Message is the message given with each match where %s is replaced with
+	// the verb to use instead
+	Message string
+}
+
+// UseNominalizationProcessor is a convenience variable for referencing a NominalizationProcessor
+var UseNominalizationProcessor = NominalizationProcessor{Message: "This hides the action in a noun. Try using the verb '%s'."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "nominal",
+		Name:        "Nominalizations",
+		Legend:      "Zombie Nouns",
+		Description: "Nominalizations, or zombie nouns, are verbs turned into nouns such as \"the implementation of\" or \"make a decision\". They bury the action of a sentence and make it longer. Using the verb itself is usually clearer.",
+		Message:     UseNominalizationProcessor.Message,
+		Category:    "clarity",
+		Color:       "130, 130, 40",
+		New: func(r rules.Rule) rules.Processor {
+			return NominalizationProcessor{Message: r.Message}
+		},
+	})
+}
+
+// Process handles the processing for nominalization matches. Light verb
+// constructions are given the verb in the same tense as a suggestion unless
+// a preposition the verb wouldn't take follows, as in "have a discussion
+// about". The suffix patterns only name the verb in the message since the
+// rest of the sentence usually needs rewording too.
+func (p NominalizationProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	var taken [][]int
+
+	normalized := NormalizeForMatching(c.Data)
+
+	add := func(found []int, verb string, suggestions []string) {
+		indices := RuneIndices(normalized, found[:2])
+		if overlapsAny(taken, indices) {
+			return
+		}
+		taken = append(taken, indices)
+
+		match := RuneSlice(c.Data, indices[0], indices[1])
+		m := rules.NewMatch(match, "nominal", indices, FormatMessage(p.Message, verb))
+		for _, suggestion := range suggestions {
+			m.Suggestions = append(m.Suggestions, MatchCase(match, suggestion))
+		}
+
+		c.Matches = append(c.Matches, m)
+		c.Score += 1
+	}
+
+	for _, lv := range lightVerbPatterns {
+		for _, found := range lv.pattern.FindAllStringSubmatchIndex(normalized, -1) {
+			if followingPreposition.MatchString(normalized[found[1]:]) {
+				add(found, lv.verb, nil)
+				continue
+			}
+
+			used := strings.ToLower(normalized[found[2]:found[3]])
+			add(found, lv.verb, []string{conjugateLike(lv.verb, lv.light, used)})
+		}
+	}
+
+	for _, found := range nominalPattern.FindAllStringSubmatchIndex(normalized, -1) {
+		noun := strings.ToLower(normalized[found[6]:found[7]])
+		add(found, nominalVerbs[noun], nil)
+	}
+
+	return c
+}
+
+// conjugateLike puts the first word of a verb phrase such as "refer to" into
+// the same form the light verb was used in
+func conjugateLike(phrase string, light string, used string) string {
+	words := strings.Fields(phrase)
+
+	for form := formBase; form <= formParticiple; form++ {
+		if Conjugate(light, form) == used {
+			words[0] = Conjugate(words[0], form)
+			break
+		}
+	}
+
+	return strings.Join(words, " ")
+}
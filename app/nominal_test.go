@@ -0,0 +1,38 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestLightVerbs(t *testing.T) {
+	tests := []struct {
+		data        string
+		match       string
+		suggestions []string
+	}{
+		{"We had a discussion.", "had a discussion", []string{"discussed"}},
+		{"We had a discussion about the budget.", "had a discussion", nil},
+		{"She made a payment of five dollars.", "made a payment", nil},
+		{"They made a reference to it.", "made a reference to", []string{"referred to"}},
+		{"She makes references to it.", "makes references to", []string{"refers to"}},
+		{"We did an analysis of the data.", "did an analysis of", []string{"analyzed"}},
+		{"Give consideration to the cost.", "Give consideration to", []string{"Consider"}},
+		{"They made decisions quickly.", "made decisions", []string{"decided"}},
+	}
+
+	for _, test := range tests {
+		c := UseNominalizationProcessor.Process(rules.NewChunk(0, test.data))
+		if len(c.Matches) != 1 {
+			t.Errorf("%q gave %d matches, want 1", test.data, len(c.Matches))
+			continue
+		}
+
+		m := c.Matches[0]
+		if m.Match != test.match || !reflect.DeepEqual(m.Suggestions, test.suggestions) {
+			t.Errorf("%q gave %q with %q, want %q with %q", test.data, m.Match, m.Suggestions, test.match, test.suggestions)
+		}
+	}
+}
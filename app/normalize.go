@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// matchingReplacer swaps typographic characters for the plain ASCII ones the
+// processors are written for. Every replacement is a single rune so rune
+// indices in the normalized text line up with the original.
+var matchingReplacer = strings.NewReplacer(
+	"‘", "'", // left single quote
+	"’", "'", // right single quote
+	"‚", "'", // low single quote
+	"‛", "'", // reversed single quote
+	"′", "'", // prime
+	"“", "\"", // left double quote
+	"”", "\"", // right double quote
+	"„", "\"", // low double quote
+	"‟", "\"", // reversed double quote
+	"″", "\"", // double prime
+	"«", "\"", // left guillemet
+	"»", "\"", // right guillemet
+	"‐", "-", // hyphen
+	"‑", "-", // non-breaking hyphen
+	"‒", "-", // figure dash
+	"–", "-", // en dash
+	"—", "-", // em dash
+	"―", "-", // horizontal bar
+	"−", "-", // minus sign
+	"…", ".", // ellipsis
+	" ", " ", // no-break space
+	" ", " ", // en space
+	" ", " ", // em space
+	" ", " ", // thin space
+	" ", " ", // narrow no-break space
+)
+
+// NormalizeForMatching gives a version of the text with typographic quotes,
+// dashes and spaces swapped for plain ones so matching isn't thrown off by
+// them. The text shown back to users is never normalized.
+func NormalizeForMatching(s string) string {
+	return matchingReplacer.Replace(s)
+}
+
+// RuneIndex converts a byte index in s into a rune index
+func RuneIndex(s string, i int) int {
+	if i > len(s) {
+		i = len(s)
+	}
+
+	return utf8.RuneCountInString(s[:i])
+}
+
+// RuneIndices converts a list of byte indices in s into rune indices
+func RuneIndices(s string, indices []int) []int {
+	result := make([]int, len(indices))
+	for i, idx := range indices {
+		result[i] = RuneIndex(s, idx)
+	}
+
+	return result
+}
+
+// ByteIndex converts a rune index in s into a byte index
+func ByteIndex(s string, i int) int {
+	count := 0
+	for idx := range s {
+		if count == i {
+			return idx
+		}
+		count++
+	}
+
+	return len(s)
+}
+
+// RuneSlice gets the runes of s from rune index start up to end
+func RuneSlice(s string, start int, end int) string {
+	return s[ByteIndex(s, start):ByteIndex(s, end)]
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuneIndices(t *testing.T) {
+	tests := []struct {
+		s       string
+		indices []int
+		want    []int
+	}{
+		{"hello", []int{0, 5}, []int{0, 5}},
+		{"héllo", []int{0, 3, 6}, []int{0, 2, 5}},
+		{"日本語 text", []int{3, 9, 10}, []int{1, 3, 4}},
+		{"a😀b", []int{1, 5, 6}, []int{1, 2, 3}},
+		{"a\xffb", []int{1, 2, 3}, []int{1, 2, 3}},
+		{"\xff\xfe", []int{0, 1, 2}, []int{0, 1, 2}},
+		{"héllo", []int{100}, []int{5}},
+	}
+
+	for _, test := range tests {
+		got := RuneIndices(test.s, test.indices)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("RuneIndices(%q, %v) = %v, want %v", test.s, test.indices, got, test.want)
+		}
+	}
+}
+
+func TestByteIndex(t *testing.T) {
+	tests := []struct {
+		s    string
+		i    int
+		want int
+	}{
+		{"hello", 2, 2},
+		{"héllo", 2, 3},
+		{"日本語", 2, 6},
+		{"a😀b", 2, 5},
+		{"a\xffb", 2, 2},
+		{"\xff\xfe", 1, 1},
+		{"héllo", 10, 6},
+	}
+
+	for _, test := range tests {
+		if got := ByteIndex(test.s, test.i); got != test.want {
+			t.Errorf("ByteIndex(%q, %d) = %d, want %d", test.s, test.i, got, test.want)
+		}
+	}
+}
+
+func TestRuneSlice(t *testing.T) {
+	tests := []struct {
+		s          string
+		start, end int
+		want       string
+	}{
+		{"hello", 1, 3, "el"},
+		{"naïve café", 6, 10, "café"},
+		{"a😀b", 1, 2, "😀"},
+		{"a\xffb", 1, 3, "\xffb"},
+	}
+
+	for _, test := range tests {
+		if got := RuneSlice(test.s, test.start, test.end); got != test.want {
+			t.Errorf("RuneSlice(%q, %d, %d) = %q, want %q", test.s, test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestNormalizeForMatchingKeepsRunes(t *testing.T) {
+	tests := []string{
+		"It’s “quoted” — and…",
+		"a\xffb ‘c’",
+	}
+
+	for _, s := range tests {
+		normalized := NormalizeForMatching(s)
+		if got, want := len([]rune(normalized)), len([]rune(s)); got != want {
+			t.Errorf("NormalizeForMatching(%q) has %d runes, want %d", s, got, want)
+		}
+	}
+}
@@ -2,17 +2,19 @@ package main
 
 import (
 	"net/http"
-
-	unidecode "github.com/rainycape/unidecode"
 )
 
-// pasteHandler reads POST data from the textarea field and sets it in a
-// cookie for the processor to take over.
+// pasteHandler reads POST data from the textarea field and starts a new
+// session for the processor to take over.
 func pasteHandler(w http.ResponseWriter, req *http.Request) {
 	req.ParseForm()
 	data := req.Form.Get("textFile")
-	appText = unidecode.Unidecode(string(data))
+	session, err := appSessions.New(data, req.Form.Get("format"), req.Form.Get("profile"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	w.Header().Set("Location", "/process")
+	w.Header().Set("Location", "/process/"+session.ID)
 	w.WriteHeader(http.StatusTemporaryRedirect)
 }
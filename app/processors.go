@@ -3,25 +3,23 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	proc "github.com/dansackett/go-text-processors"
+	"write-better/rules"
 )
 
-// Processor is an interface which handles processing of a Chunk.
-type Processor interface {
-	Process(*Chunk) *Chunk
-}
-
 // ActiveProcessors stores a list of processors that can be used to process on
 // a given chunk. This provides an easy way to handle chaining of multiple
 // processors.
-type ActiveProcessors []Processor
+type ActiveProcessors []rules.Processor
 
 // Process method satisfies the interface for a Processor allowing us to call
 // this method on our list and run the chunk through each processor.
-func (p ActiveProcessors) Process(c *Chunk) *Chunk {
+func (p ActiveProcessors) Process(c *rules.Chunk) *rules.Chunk {
 	for _, processor := range p {
 		c = processor.Process(c)
 	}
@@ -29,18 +27,121 @@ func (p ActiveProcessors) Process(c *Chunk) *Chunk {
 	return c
 }
 
-// processorsHandler chunks and processes text
+// Pipeline holds the processors to run at each level of a document
+type Pipeline struct {
+	// Sentence processors look at one chunk at a time
+	Sentence ActiveProcessors
+	// Paragraph processors look at the chunks of one paragraph at a time
+	Paragraph []rules.ParagraphProcessor
+	// Document processors look at every chunk at once
+	Document []rules.DocumentProcessor
+}
+
+// Run sends the chunks through the sentence, paragraph and then document
+// processors before rendering the matches as HTML. The chunks are left in
+// order.
+func (p Pipeline) Run(chunks rules.Chunks) {
+	ProcessChunks(chunks, p.Sentence)
+	sort.Sort(rules.ByChunk(chunks))
+
+	for _, paragraph := range Paragraphs(chunks) {
+		for _, processor := range p.Paragraph {
+			processor.ProcessParagraph(paragraph)
+		}
+	}
+
+	for _, processor := range p.Document {
+		processor.ProcessDocument(chunks)
+	}
+
+	// This needs to be the last one since it renders the matches found by the
+	// others
+	ProcessChunks(chunks, UseHTMLProcessor)
+}
+
+// Paragraphs groups chunks which are in order into their paragraphs
+func Paragraphs(chunks rules.Chunks) []rules.Chunks {
+	var paragraphs []rules.Chunks
+
+	for _, chunk := range chunks {
+		if chunk.IsNewParagraph || len(paragraphs) == 0 {
+			paragraphs = append(paragraphs, rules.Chunks{})
+		}
+
+		last := len(paragraphs) - 1
+		paragraphs[last] = append(paragraphs[last], chunk)
+	}
+
+	return paragraphs
+}
+
+// processorsHandler chunks and processes the text for a session
 func processorsHandler(w http.ResponseWriter, r *http.Request) {
-	var wg sync.WaitGroup
+	session, ok := appSessions.Get(strings.TrimPrefix(r.URL.Path, "/process/"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
 
-	// Chunk the text into sentences
-	c := NewSentenceChunker(appText)
-	chunks, _ := c.Chunk()
+	profile, err := appConfig.Profile(session.Profile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Asking for the same session again only redirects to its results
+	err = appSessions.Process(session, func(session *Session) (rules.Chunks, Summary, error) {
+		return Analyze(session.Text, session.Format, profile)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", "/results/"+session.ID)
+	w.WriteHeader(http.StatusTemporaryRedirect)
+}
+
+// Analyze chunks the text into sentences based on its format and runs them
+// through the processors enabled in the profile. The chunks are returned in
+// order.
+func Analyze(text string, format string, profile Profile) (rules.Chunks, Summary, error) {
+	segmenter, err := LookupSegmenter(profile.Language)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := NewChunker(format, text, segmenter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chunks, summary, err := c.Chunk()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile.Processors().Run(chunks)
+
+	// Mark how important each match is for this profile
+	for _, chunk := range chunks {
+		for _, match := range chunk.Matches {
+			match.Severity = profile.Severity(match.Label)
+		}
+	}
+
+	return chunks, summary, nil
+}
+
+// ProcessChunks runs every chunk through the given processors. Each chunk is
+// sent into its own goroutine since they don't depend on each other.
+func ProcessChunks(chunks rules.Chunks, processors rules.Processor) {
+	var wg sync.WaitGroup
 
 	// Send each chunk into a gorountine to process
 	for _, c := range chunks {
 		wg.Add(1)
-		go func(c *Chunk) {
+		go func(c *rules.Chunk) {
 			defer wg.Done()
 			c = processors.Process(c)
 		}(c)
@@ -48,143 +149,313 @@ func processorsHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Wait for the processing to finish
 	wg.Wait()
-
-	appResult = chunks
-
-	w.Header().Set("Location", "/results")
-	w.WriteHeader(http.StatusTemporaryRedirect)
 }
 
 // doTextProcessor is a convenience function to make this more DRY. It runs
 // the processors from go-text-processors giving the same treatment to each
-// processor.
-func doTextProcessor(p proc.TextProcessor, label string, c *Chunk, msg string) *Chunk {
-	res := p.Run(c.Data)
+// processor. The suggester may be nil when there are no replacements to give.
+// Match indices are rune indices within the chunk's Data.
+func doTextProcessor(p proc.TextProcessor, label string, c *rules.Chunk, msg string, suggest Suggester) *rules.Chunk {
+	// The processors match on a normalized copy of the text and give back
+	// byte indices into it. Those are turned into rune indices which line up
+	// with the original text.
+	normalized := NormalizeForMatching(c.Data)
+	res := p.Run(normalized)
 
 	for _, match := range res.Matches {
-		formattedMsg := fmt.Sprintf(msg)
-		c.Matches = append(c.Matches, NewMatch(match.Match, label, match.Indices, formattedMsg))
+		indices := RuneIndices(normalized, match.Indices)
+		text := RuneSlice(c.Data, indices[0], indices[1])
+
+		m := rules.NewMatch(text, label, indices, msg)
+		if suggest != nil {
+			m.Suggestions = suggest(NormalizeForMatching(text))
+		}
+
+		c.Matches = append(c.Matches, m)
 		c.Score += 1
 	}
 
 	return c
 }
 
-// PassiveVoiceProcessor is an empty struct for processing the passive voice
-type PassiveVoiceProcessor struct{}
+// PassiveVoiceProcessor processes the passive voice
+type PassiveVoiceProcessor struct {
+	// Message is the message given with each match
+	Message string
+}
 
 // UsePassiveVoiceProcessor is a convenience variable for referencing a PassiveVoiceProcessor
-var UsePassiveVoiceProcessor PassiveVoiceProcessor
+var UsePassiveVoiceProcessor = PassiveVoiceProcessor{Message: "This is considered passive voice."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "passive",
+		Name:        "Passive Voice",
+		Legend:      "Passive Phrases",
+		Description: "Passive voice inverts the logical progression of ideas making those ideas hard to follow, especially when they are complex or technical. Another strike against passive voice is the wordiness that inevitably accompanies it.",
+		Message:     UsePassiveVoiceProcessor.Message,
+		Category:    "clarity",
+		Color:       "215, 44, 44",
+		New: func(r rules.Rule) rules.Processor {
+			return PassiveVoiceProcessor{Message: r.Message}
+		},
+	})
+}
 
 // Process handles the processing for passive voice matches
-func (_ PassiveVoiceProcessor) Process(c *Chunk) *Chunk {
-	msg := "This is considered passive voice."
-	return doTextProcessor(proc.PassiveVoiceProcessor(), "passive", c, msg)
+func (p PassiveVoiceProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	return doTextProcessor(proc.PassiveVoiceProcessor(), "passive", c, p.Message, nil)
 }
 
-// WeaselWordProcessor is an empty struct for processing weasel words
-type WeaselWordProcessor struct{}
+// WeaselWordProcessor processes weasel words
+type WeaselWordProcessor struct {
+	// Message is the message given with each match
+	Message string
+}
 
 // UseWeaselWordProcessor is a convenience variable for referencing a WeaselWordProcessor
-var UseWeaselWordProcessor WeaselWordProcessor
+var UseWeaselWordProcessor = WeaselWordProcessor{Message: "This is considered a weasel word."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "weasel",
+		Name:        "Weasel Words",
+		Legend:      "Weasel Words",
+		Description: "Weasel words create an impression that a specific or meaningful statement has been made, when only a vague or ambiguous claim has been communicated, enabling the specific meaning to be denied if the statement is challenged.",
+		Message:     UseWeaselWordProcessor.Message,
+		Category:    "clarity",
+		Color:       "146, 96, 44",
+		New: func(r rules.Rule) rules.Processor {
+			return WeaselWordProcessor{Message: r.Message}
+		},
+	})
+}
 
 // Process handles the processing for weasel word matches
-func (_ WeaselWordProcessor) Process(c *Chunk) *Chunk {
-	msg := "This is considered a weasel word."
-	return doTextProcessor(proc.WeaselWordProcessor(), "weasel", c, msg)
+func (p WeaselWordProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	return doTextProcessor(proc.WeaselWordProcessor(), "weasel", c, p.Message, nil)
 }
 
-// TooWordyProcessor is an empty struct for processing wordy phrases
-type TooWordyProcessor struct{}
+// TooWordyProcessor processes wordy phrases
+type TooWordyProcessor struct {
+	// Message is the message given with each match
+	Message string
+}
 
 // UseTooWordyProcessor is a convenience variable for referencing a TooWordyProcessor
-var UseTooWordyProcessor TooWordyProcessor
+var UseTooWordyProcessor = TooWordyProcessor{Message: "This is considered a wordy phrase."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "wordy",
+		Name:        "Too Wordy Phrases",
+		Legend:      "Wordy Phrases",
+		Description: "It's easy to add filler that hurts your point rather than strengthening it. These wordy phrases hide the true intent of your prose.",
+		Message:     UseTooWordyProcessor.Message,
+		Category:    "concision",
+		Color:       "146, 204, 44",
+		New: func(r rules.Rule) rules.Processor {
+			return TooWordyProcessor{Message: r.Message}
+		},
+	})
+}
 
 // Process handles the processing for wordy phrase matches
-func (_ TooWordyProcessor) Process(c *Chunk) *Chunk {
-	msg := "This is considered a wordy phrase."
-	return doTextProcessor(proc.TooWordyProcessor(), "wordy", c, msg)
+func (p TooWordyProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	return doTextProcessor(proc.TooWordyProcessor(), "wordy", c, p.Message, SuggestFromTable(wordySuggestions))
 }
 
-// AdverbProcessor is an empty struct for processing adverbs
-type AdverbProcessor struct{}
+// AdverbProcessor processes adverbs
+type AdverbProcessor struct {
+	// Message is the message given with each match
+	Message string
+}
 
 // UseAdverbProcessor is a convenience variable for referencing a AdverbProcessor
-var UseAdverbProcessor AdverbProcessor
+var UseAdverbProcessor = AdverbProcessor{Message: "This is an adverb."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "adverb",
+		Name:        "Adverbs",
+		Legend:      "Adverbs",
+		Description: "Adverbs are good in moderation but are one of the most overused parts of writing. They are repetitive and dillute the actual verb they are modifying.",
+		Message:     UseAdverbProcessor.Message,
+		Category:    "concision",
+		Color:       "32, 204, 133",
+		New: func(r rules.Rule) rules.Processor {
+			return AdverbProcessor{Message: r.Message}
+		},
+	})
+}
 
 // Process handles the processing for adverb matches
-func (_ AdverbProcessor) Process(c *Chunk) *Chunk {
-	msg := "This is an adverb."
-	return doTextProcessor(proc.AdverbProcessor(), "adverb", c, msg)
+func (p AdverbProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	return doTextProcessor(proc.AdverbProcessor(), "adverb", c, p.Message, nil)
 }
 
-// ClicheProcessor is an empty struct for processing cliches
-type ClicheProcessor struct{}
+// ClicheProcessor processes cliches
+type ClicheProcessor struct {
+	// Message is the message given with each match
+	Message string
+}
 
 // UseClicheProcessor is a convenience variable for referencing a ClicheProcessor
-var UseClicheProcessor ClicheProcessor
+var UseClicheProcessor = ClicheProcessor{Message: "This is a cliche."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "cliche",
+		Name:        "Cliches",
+		Legend:      "Cliches",
+		Description: "Cliches are expressions which have become overused to the point of losing its original meaning or effect, even to the point of being irritating.",
+		Message:     UseClicheProcessor.Message,
+		Category:    "style",
+		Color:       "32, 105, 0",
+		New: func(r rules.Rule) rules.Processor {
+			return ClicheProcessor{Message: r.Message}
+		},
+	})
+}
 
 // Process handles the processing for cliche matches
-func (_ ClicheProcessor) Process(c *Chunk) *Chunk {
-	msg := "This is a cliche."
-	return doTextProcessor(proc.ClicheProcessor(), "cliche", c, msg)
+func (p ClicheProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	return doTextProcessor(proc.ClicheProcessor(), "cliche", c, p.Message, nil)
 }
 
-// LexicalIllusionProcessor is an empty struct for processing repeated words
-type LexicalIllusionProcessor struct{}
+// LexicalIllusionProcessor processes repeated words
+type LexicalIllusionProcessor struct {
+	// Message is the message given with each match
+	Message string
+}
 
 // UseLexicalIllusionProcessor is a convenience variable for referencing a LexicalIllusionProcessor
-var UseLexicalIllusionProcessor LexicalIllusionProcessor
+var UseLexicalIllusionProcessor = LexicalIllusionProcessor{Message: "This a repeated word."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "illusion",
+		Name:        "Lexical Illusion",
+		Legend:      "Repeated Words",
+		Description: "Lexical illusions are hard to spot since our brain can scan text so quickly. By definition it is a repeated word.",
+		Message:     UseLexicalIllusionProcessor.Message,
+		Category:    "correctness",
+		Color:       "199, 105, 0",
+		New: func(r rules.Rule) rules.Processor {
+			return LexicalIllusionProcessor{Message: r.Message}
+		},
+	})
+}
 
 // Process handles the processing for repeated word matches
-func (_ LexicalIllusionProcessor) Process(c *Chunk) *Chunk {
-	msg := "This a repeated word."
-	return doTextProcessor(proc.LexicalIllusionProcessor(), "illusion", c, msg)
+func (p LexicalIllusionProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	return doTextProcessor(proc.LexicalIllusionProcessor(), "illusion", c, p.Message, suggestSingleWord)
+}
+
+// SentenceLengthProcessor processes a sentence's length against its limits
+type SentenceLengthProcessor struct {
+	// Long is the length a sentence must pass to be considered long
+	Long int
+	// VeryLong is the length a sentence must pass to be considered VERY long
+	VeryLong int
+	// Message is the message given with each match where %s is replaced with
+	// how long the sentence is
+	Message string
+}
+
+// UseSentenceLengthProcessor is a convenience variable for referencing a
+// SentenceLengthProcessor with the default limits
+var UseSentenceLengthProcessor = SentenceLengthProcessor{
+	Long:     130,
+	VeryLong: 160,
+	Message:  "This is a %s sentence.",
 }
 
-// SentenceLengthProcessor is an empty struct for processing a sentence's length
-type SentenceLengthProcessor struct{}
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "length",
+		Name:        "Sentence Length",
+		Legend:      "Long Sentences",
+		Description: "Long sentences are a quick way to lose a reader.  Sometimes things you want to convey are better said in short bursts.",
+		Message:     UseSentenceLengthProcessor.Message,
+		Category:    "structure",
+		Color:       "199, 142, 37",
+		Thresholds:  map[string]int{"long": 1, "very_long": 1},
+		Validate: func(r rules.Rule) error {
+			long := r.Threshold("long", UseSentenceLengthProcessor.Long)
+			if veryLong := r.Threshold("very_long", UseSentenceLengthProcessor.VeryLong); veryLong < long {
+				return fmt.Errorf("threshold \"very_long\" (%d) must be at least \"long\" (%d)", veryLong, long)
+			}
 
-// UseSentenceLengthProcessor is a convenience variable for referencing a SentenceLengthProcessor
-var UseSentenceLengthProcessor SentenceLengthProcessor
+			return nil
+		},
+		New: func(r rules.Rule) rules.Processor {
+			return SentenceLengthProcessor{
+				Long:     r.Threshold("long", UseSentenceLengthProcessor.Long),
+				VeryLong: r.Threshold("very_long", UseSentenceLengthProcessor.VeryLong),
+				Message:  r.Message,
+			}
+		},
+	})
+}
 
 // Process handles the processing for long sentence matches
-func (_ SentenceLengthProcessor) Process(c *Chunk) *Chunk {
+func (p SentenceLengthProcessor) Process(c *rules.Chunk) *rules.Chunk {
 	var indices []int
 
-	if len(c.Data) > 160 {
-		msg := fmt.Sprintf("This is a VERY long sentence.")
-		c.Matches = append(c.Matches, NewMatch("", "length", indices, msg))
+	length := utf8.RuneCountInString(c.Data)
+	if length > p.VeryLong {
+		msg := FormatMessage(p.Message, "VERY long")
+		c.Matches = append(c.Matches, rules.NewMatch("", "length", indices, msg))
 		c.Score += 1
-	} else if len(c.Data) > 130 {
-		msg := fmt.Sprintf("This is a long sentence.")
-		c.Matches = append(c.Matches, NewMatch("", "length", indices, msg))
+	} else if length > p.Long {
+		msg := FormatMessage(p.Message, "long")
+		c.Matches = append(c.Matches, rules.NewMatch("", "length", indices, msg))
 		c.Score += 1
 	}
 
 	return c
 }
 
-// StartsWithProcessor is an empty struct for processing a sentence's first phrase
-type StartsWithProcessor struct{}
+// StartsWithProcessor processes a sentence's first phrase
+type StartsWithProcessor struct {
+	// Message is the message given with each match where %s is replaced with
+	// the starting phrase
+	Message string
+}
 
 // UseStartsWithProcessor is a convenience variable for referencing a StartsWithProcessor
-var UseStartsWithProcessor StartsWithProcessor
+var UseStartsWithProcessor = StartsWithProcessor{Message: "This sentence starts with '%s'. Consider changing it."}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "startswith",
+		Name:        "Starting Phrases",
+		Legend:      "Sentence Starters",
+		Description: "Sentence starters should be concise rather than vague.  Phrases such as \"there is\", \"there are\", and \"so\" should be avoided.",
+		Message:     UseStartsWithProcessor.Message,
+		Category:    "style",
+		Color:       "255, 0, 37",
+		New: func(r rules.Rule) rules.Processor {
+			return StartsWithProcessor{Message: r.Message}
+		},
+	})
+}
 
 // Process handles the processing for first phrase matches
-func (_ StartsWithProcessor) Process(c *Chunk) *Chunk {
+func (p StartsWithProcessor) Process(c *rules.Chunk) *rules.Chunk {
 	if strings.ToLower(c.FirstWord) == "so" {
-		msg := fmt.Sprintf("This sentence starts with 'so'. Consider changing it.")
-		c.Matches = append(c.Matches, NewMatch("", "startswith", getStartsWithIndices("s", 2, c), msg))
+		msg := FormatMessage(p.Message, "so")
+		c.Matches = append(c.Matches, rules.NewMatch("", "startswith", getStartsWithIndices("s", 2, c), msg))
 		c.Score += 1
 	} else if strings.ToLower(c.FirstWord) == "there" {
 		if strings.HasPrefix(strings.ToLower(c.Data), "there is") {
-			msg := fmt.Sprintf("This sentence starts with 'there is'. Consider changing it.")
-			c.Matches = append(c.Matches, NewMatch("", "startswith", getStartsWithIndices("t", 8, c), msg))
+			msg := FormatMessage(p.Message, "there is")
+			c.Matches = append(c.Matches, rules.NewMatch("", "startswith", getStartsWithIndices("t", 8, c), msg))
 			c.Score += 1
 		} else if strings.HasPrefix(strings.ToLower(c.Data), "there are") {
-			msg := fmt.Sprintf("This sentence starts with 'there are'. Consider changing it.")
-			c.Matches = append(c.Matches, NewMatch("", "startswith", getStartsWithIndices("t", 9, c), msg))
+			msg := FormatMessage(p.Message, "there are")
+			c.Matches = append(c.Matches, rules.NewMatch("", "startswith", getStartsWithIndices("t", 9, c), msg))
 			c.Score += 1
 		}
 	}
@@ -192,15 +463,12 @@ func (_ StartsWithProcessor) Process(c *Chunk) *Chunk {
 	return c
 }
 
-// getStartsWithIndices helps find the correct indices for a starting phrase
-// in cases the string begins with quotes or other characters.
-func getStartsWithIndices(str string, strSize int, c *Chunk) []int {
-	if strings.ToLower(string(c.Data[0])) == str {
-		return []int{0, strSize}
-	}
-	firstOcc := 1
-	for i, s := range c.Data {
-		if strings.ToLower(string(s)) == str {
+// getStartsWithIndices helps find the correct rune indices for a starting
+// phrase in cases the string begins with quotes or other characters.
+func getStartsWithIndices(str string, strSize int, c *rules.Chunk) []int {
+	firstOcc := 0
+	for i, r := range []rune(c.Data) {
+		if strings.ToLower(string(r)) == str {
 			firstOcc = i
 			break
 		}
@@ -208,30 +476,169 @@ func getStartsWithIndices(str string, strSize int, c *Chunk) []int {
 	return []int{firstOcc, firstOcc + strSize}
 }
 
+// ParagraphLengthProcessor processes a paragraph's length against its limits
+type ParagraphLengthProcessor struct {
+	// Sentences is the number of sentences a paragraph must pass to be
+	// considered long
+	Sentences int
+	// Words is the number of words a paragraph must pass to be considered long
+	Words int
+	// Message is the message given with each match where %s is replaced with
+	// how long the paragraph is
+	Message string
+}
+
+// UseParagraphLengthProcessor is a convenience variable for referencing a
+// ParagraphLengthProcessor with the default limits
+var UseParagraphLengthProcessor = ParagraphLengthProcessor{
+	Sentences: 8,
+	Words:     200,
+	Message:   "This paragraph is long with %s.",
+}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "paragraph",
+		Name:        "Paragraph Length",
+		Legend:      "Long Paragraphs",
+		Description: "A wall of text is tiring to read.  Breaking long paragraphs up gives readers a place to pause and makes each point easier to find.",
+		Message:     UseParagraphLengthProcessor.Message,
+		Category:    "structure",
+		Color:       "112, 66, 160",
+		Thresholds:  map[string]int{"sentences": 1, "words": 1},
+		NewParagraph: func(r rules.Rule) rules.ParagraphProcessor {
+			return ParagraphLengthProcessor{
+				Sentences: r.Threshold("sentences", UseParagraphLengthProcessor.Sentences),
+				Words:     r.Threshold("words", UseParagraphLengthProcessor.Words),
+				Message:   r.Message,
+			}
+		},
+	})
+}
+
+// ProcessParagraph handles the processing for long paragraph matches. The
+// match covers the first sentence of the paragraph.
+func (p ParagraphLengthProcessor) ProcessParagraph(paragraph rules.Chunks) {
+	var indices []int
+
+	words := 0
+	for _, c := range paragraph {
+		words += len(Words(c.Data))
+	}
+
+	var length string
+	if len(paragraph) > p.Sentences {
+		length = fmt.Sprintf("%d sentences", len(paragraph))
+	} else if words > p.Words {
+		length = fmt.Sprintf("%d words", words)
+	} else {
+		return
+	}
+
+	first := paragraph[0]
+	first.Matches = append(first.Matches, rules.NewMatch("", "paragraph", indices, FormatMessage(p.Message, length)))
+	first.Score += 1
+}
+
+// RepeatedOpenerProcessor processes runs of sentences in a paragraph which
+// start with the same word
+type RepeatedOpenerProcessor struct {
+	// Run is the number of sentences in a row which must share a first word
+	// to be flagged
+	Run int
+	// Message is the message given with each match where %s is replaced with
+	// the shared first word
+	Message string
+}
+
+// UseRepeatedOpenerProcessor is a convenience variable for referencing a
+// RepeatedOpenerProcessor with the default run length
+var UseRepeatedOpenerProcessor = RepeatedOpenerProcessor{
+	Run:     3,
+	Message: "Several sentences in a row start with '%s'. Try varying how they begin.",
+}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "opener",
+		Name:        "Repeated Openers",
+		Legend:      "Repeated Openers",
+		Description: "Starting sentence after sentence with the same word, such as \"The\" or \"This\", gives writing a monotonous rhythm.  Varying how sentences begin keeps readers interested.",
+		Message:     UseRepeatedOpenerProcessor.Message,
+		Category:    "style",
+		Color:       "0, 128, 128",
+		Thresholds:  map[string]int{"run": 2},
+		NewParagraph: func(r rules.Rule) rules.ParagraphProcessor {
+			return RepeatedOpenerProcessor{
+				Run:     r.Threshold("run", UseRepeatedOpenerProcessor.Run),
+				Message: r.Message,
+			}
+		},
+	})
+}
+
+// ProcessParagraph handles the processing for repeated opener matches. Every
+// sentence in a long enough run is flagged at its first word.
+func (p RepeatedOpenerProcessor) ProcessParagraph(paragraph rules.Chunks) {
+	start := 0
+	for i := 1; i <= len(paragraph); i++ {
+		opener := strings.ToLower(paragraph[start].FirstWord)
+
+		// Keep going while the run continues
+		if i < len(paragraph) && opener != "" && strings.ToLower(paragraph[i].FirstWord) == opener {
+			continue
+		}
+
+		if opener != "" && i-start >= p.Run {
+			for _, c := range paragraph[start:i] {
+				msg := FormatMessage(p.Message, c.FirstWord)
+				c.Matches = append(c.Matches, rules.NewMatch(c.FirstWord, "opener", getFirstWordIndices(c), msg))
+				c.Score += 1
+			}
+		}
+
+		start = i
+	}
+}
+
+// getFirstWordIndices finds the rune indices of the first word in a chunk
+// skipping any spaces or quotes before it
+func getFirstWordIndices(c *rules.Chunk) []int {
+	first := 0
+	for i, r := range []rune(c.Data) {
+		if IsAlphaNumeric(r) {
+			first = i
+			break
+		}
+	}
+
+	return []int{first, first + utf8.RuneCountInString(c.FirstWord)}
+}
+
 // HTMLProcessor applies HTML tags to the sentence for the frontend
 type HTMLProcessor struct{}
 
 // UseHTMLProcessor is a convenience variable for referencing a HTMLProcessor
 var UseHTMLProcessor HTMLProcessor
 
-// Process applies the HTML tags to the string
-func (_ HTMLProcessor) Process(c *Chunk) *Chunk {
+// Process applies the HTML tags to the string and stores it as the HTML for
+// the chunk so the original Data is left alone
+func (_ HTMLProcessor) Process(c *rules.Chunk) *rules.Chunk {
 	nodes := ToCharNodes(c.Data)
-	nodesLen := len(nodes)
-
-	if len(c.Matches) > 0 {
-		for _, match := range c.Matches {
-			if len(match.Indices) == 0 {
-				nodes[0].AddBefore(OpenTag(match.Label, match.Message))
-				nodes[nodesLen-1].AddAfter(CloseTag())
-			} else {
-				nodes[match.Indices[0]].AddBefore(OpenTag(match.Label, match.Message))
-				nodes[match.Indices[1]-1].AddAfter(CloseTag())
-			}
+
+	for _, match := range c.Matches {
+		// Spans are clamped to the chunk so a bad match can't highlight
+		// outside of it. Empty spans have nothing to highlight.
+		first, last := c.SpanOf(match)
+		if first == last {
+			continue
 		}
+
+		nodes[first].AddBefore(OpenTag(match.Label, match.Tooltip()))
+		nodes[last-1].AddAfter(CloseTag())
 	}
 
-	c.Data = nodes.ToString()
+	c.HTML = nodes.ToString()
 
 	return c
 }
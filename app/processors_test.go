@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestConfiguredMessages(t *testing.T) {
+	long := "This sentence has quite a few words in it so that it will pass the limit."
+
+	tests := []struct {
+		processor rules.Processor
+		data      string
+		want      string
+	}{
+		{SentenceLengthProcessor{Long: 5, VeryLong: 100, Message: "Shorten this."}, long, "Shorten this."},
+		{SentenceLengthProcessor{Long: 5, VeryLong: 100, Message: "Over 50% too %s."}, long, "Over 50% too long."},
+		{StartsWithProcessor{Message: "Don't start with it."}, "So we left.", "Don't start with it."},
+	}
+
+	for _, test := range tests {
+		c := rules.NewChunk(0, test.data)
+		c.FirstWord = Words(test.data)[0]
+
+		c = test.processor.Process(c)
+		if len(c.Matches) != 1 || c.Matches[0].Message != test.want {
+			t.Errorf("%T gave %v, want one match with %q", test.processor, c.Matches, test.want)
+		}
+	}
+}
+
+func TestRepeatedOpeners(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"The cat sat. The dog ran. The bird flew. A fish swam.", []string{"The", "The", "The"}},
+		{"The cat sat. THE dog ran. “The bird flew.”", []string{"The", "THE", "The"}},
+		{"The cat sat. The dog ran. A bird flew. The fish swam.", nil},
+		{"We left. We ate. They slept. We left. We ate. We slept.", []string{"We", "We", "We"}},
+	}
+
+	for _, test := range tests {
+		chunks, _, err := NewSentenceChunker(test.text).Chunk()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Sort(rules.ByChunk(chunks))
+		UseRepeatedOpenerProcessor.ProcessParagraph(chunks)
+
+		var got []string
+		for _, c := range chunks {
+			for _, m := range c.Matches {
+				got = append(got, m.Match)
+				if word := RuneSlice(c.Data, m.Indices[0], m.Indices[1]); word != m.Match {
+					t.Errorf("%q: the match indices give %q, want %q", test.text, word, m.Match)
+				}
+			}
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q flagged %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"write-better/rules"
+)
+
+// Readability holds the standard readability index scores for a text
+type Readability struct {
+	// FleschReadingEase scores from 0 to 100 with higher being easier
+	FleschReadingEase float64 `json:"fleschReadingEase"`
+	// FleschKincaidGrade is the U.S. school grade needed to read the text
+	FleschKincaidGrade float64 `json:"fleschKincaidGrade"`
+	// GunningFog is the years of education needed to read the text
+	GunningFog float64 `json:"gunningFog"`
+	// SMOG is the years of education needed based on polysyllables
+	SMOG float64 `json:"smog"`
+	// ColemanLiau is the grade level based on letters rather than syllables
+	ColemanLiau float64 `json:"colemanLiau"`
+	// ARI is the Automated Readability Index grade level
+	ARI float64 `json:"ari"`
+	// Grade is the average of the grade level scores
+	Grade float64 `json:"grade"`
+	// GradeLevel describes who can comfortably read the text
+	GradeLevel string `json:"gradeLevel"`
+	// Ease describes how easy the text is from the reading ease score
+	Ease string `json:"ease"`
+}
+
+// GetReadability computes the readability scores from the chunked text and
+// its summary.
+func GetReadability(chunks rules.Chunks, summary Summary) Readability {
+	var result Readability
+	var words, syllables, polysyllables, characters int
+
+	for _, chunk := range chunks {
+		for _, word := range Words(chunk.Data) {
+			count := CountSyllables(word)
+
+			words++
+			syllables += count
+			characters += utf8.RuneCountInString(word)
+			if count >= 3 {
+				polysyllables++
+			}
+		}
+	}
+
+	sentences := summary["sentences"]
+	if words == 0 || sentences == 0 {
+		return result
+	}
+
+	wordsPerSentence := float64(words) / float64(sentences)
+	syllablesPerWord := float64(syllables) / float64(words)
+	lettersPer100 := float64(summary["letters"]) / float64(words) * 100
+	sentencesPer100 := float64(sentences) / float64(words) * 100
+
+	result.FleschReadingEase = round(206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord)
+	result.FleschKincaidGrade = round(0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59)
+	result.GunningFog = round(0.4 * (wordsPerSentence + 100*float64(polysyllables)/float64(words)))
+	result.SMOG = round(1.043*math.Sqrt(float64(polysyllables)*30/float64(sentences)) + 3.1291)
+	result.ColemanLiau = round(0.0588*lettersPer100 - 0.296*sentencesPer100 - 15.8)
+	result.ARI = round(4.71*float64(characters)/float64(words) + 0.5*wordsPerSentence - 21.43)
+
+	result.Grade = round((result.FleschKincaidGrade + result.GunningFog + result.SMOG + result.ColemanLiau + result.ARI) / 5)
+	result.GradeLevel = GradeLevel(result.Grade)
+	result.Ease = EaseLevel(result.FleschReadingEase)
+
+	return result
+}
+
+// CountSyllables estimates the number of syllables in a word by counting the
+// groups of vowels, ignoring a silent e and an "-ed" which isn't spoken.
+func CountSyllables(word string) int {
+	word = strings.ToLower(word)
+	if len(word) <= 3 {
+		return 1
+	}
+
+	// Drop endings which usually don't add a syllable. "-ed" does after a t
+	// or d as in "wanted".
+	if strings.HasSuffix(word, "ed") && strings.ContainsRune("td", rune(word[len(word)-3])) {
+		word = word[:len(word)-1]
+	} else if strings.HasSuffix(word, "es") || strings.HasSuffix(word, "ed") {
+		word = word[:len(word)-2]
+	} else if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") {
+		word = word[:len(word)-1]
+	}
+
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+
+	if count == 0 {
+		return 1
+	}
+
+	return count
+}
+
+// GradeLevel describes a U.S. school grade level score
+func GradeLevel(grade float64) string {
+	switch {
+	case grade < 6:
+		return "Elementary school"
+	case grade < 9:
+		return "Middle school"
+	case grade < 13:
+		return "High school"
+	case grade < 17:
+		return "College"
+	}
+
+	return "College graduate"
+}
+
+// EaseLevel describes a Flesch reading ease score
+func EaseLevel(score float64) string {
+	switch {
+	case score >= 90:
+		return "Very easy"
+	case score >= 80:
+		return "Easy"
+	case score >= 70:
+		return "Fairly easy"
+	case score >= 60:
+		return "Standard"
+	case score >= 50:
+		return "Fairly difficult"
+	case score >= 30:
+		return "Difficult"
+	}
+
+	return "Very confusing"
+}
+
+// round rounds a score to one decimal place
+func round(val float64) float64 {
+	return math.Floor(val*10+0.5) / 10
+}
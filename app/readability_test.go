@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestCountSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"cat", 1},
+		{"jumped", 1},
+		{"played", 1},
+		{"wanted", 2},
+		{"needed", 2},
+		{"added", 2},
+		{"table", 2},
+		{"make", 1},
+		{"beautiful", 3},
+	}
+
+	for _, test := range tests {
+		if got := CountSyllables(test.word); got != test.want {
+			t.Errorf("CountSyllables(%q) = %d, want %d", test.word, got, test.want)
+		}
+	}
+}
+
+func TestReadabilityCountsCharacters(t *testing.T) {
+	ari := func(text string) float64 {
+		chunks, summary, err := NewSentenceChunker(text).Chunk()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return GetReadability(chunks, summary).ARI
+	}
+
+	if accented, plain := ari("The naïve café owner smiled."), ari("The naive cafe owner smiled."); accented != plain {
+		t.Errorf("ARI is %v with accents and %v without", accented, plain)
+	}
+}
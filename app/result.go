@@ -6,31 +6,27 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"write-better/rules"
 )
 
 // Average words per minute
 const AvgReadingSpeed = 275
 
-// resultHandler reads the result cookie, parses it, and gets it ready to be
-// used in a template to show users how their text finishes.
+// resultHandler reads the session results, parses them, and gets them ready
+// to be used in a template to show users how their text finishes.
 func resultHandler(w http.ResponseWriter, req *http.Request) {
-	var score int
 	var fullText []string
 	var curStr bytes.Buffer
 
-	chunks := appResult
-	matches := map[string]int{
-		"passive":    0,
-		"weasel":     0,
-		"wordy":      0,
-		"adverb":     0,
-		"cliche":     0,
-		"illusion":   0,
-		"length":     0,
-		"startswith": 0,
+	chunks, summary, ok := appSessions.Results(strings.TrimPrefix(req.URL.Path, "/results/"))
+	if !ok {
+		http.NotFound(w, req)
+		return
 	}
 
-	sort.Sort(ByChunk(chunks))
+	score, matches := CountMatches(chunks)
 
 	for _, chunk := range chunks {
 		// Build paragraphs
@@ -40,19 +36,10 @@ func resultHandler(w http.ResponseWriter, req *http.Request) {
 
 			curStr.Reset()
 			curStr.WriteString("<p>")
-			curStr.WriteString(chunk.Data)
+			curStr.WriteString(chunk.HTML)
 		} else {
-			curStr.WriteString(chunk.Data)
-		}
-
-		// Build match sums
-		if len(chunk.Matches) > 0 {
-			for _, match := range chunk.Matches {
-				matches[match.Label] += 1
-			}
+			curStr.WriteString(chunk.HTML)
 		}
-
-		score += chunk.Score
 	}
 
 	// Make sure buffer is cleaned out
@@ -62,11 +49,15 @@ func resultHandler(w http.ResponseWriter, req *http.Request) {
 
 	// Build data for the template
 	returnData := map[string]interface{}{
-		"score":    score,
-		"matches":  matches,
-		"summary":  appSummary,
-		"readTime": GetReadTime(),
-		"fullText": fullText,
+		"score":       score,
+		"matches":     matches,
+		"processors":  rules.RegisteredProcessors(),
+		"summary":     summary,
+		"readTime":    GetReadTime(summary["words"]),
+		"readability": GetReadability(chunks, summary),
+		"frequency":   GetWordFrequency(chunks, summary),
+		"sentences":   GetSentenceStats(chunks),
+		"fullText":    fullText,
 	}
 
 	// Render the template
@@ -74,10 +65,125 @@ func resultHandler(w http.ResponseWriter, req *http.Request) {
 	RenderTemplate(t, w, returnData)
 }
 
-func GetReadTime() string {
+// MatchResult is a Match placed at its absolute position within the full text
+type MatchResult struct {
+	// Match is the actual word / phrase that matches
+	Match string `json:"match"`
+	// Label is the type of processor
+	Label string `json:"label"`
+	// Message is the message from the processor
+	Message string `json:"message"`
+	// Severity is how important the match is in the profile being used
+	Severity string `json:"severity"`
+	// Suggestions are concrete replacements for the match
+	Suggestions []string `json:"suggestions"`
+	// Source describes where the match came from in the source document
+	Source string `json:"source,omitempty"`
+	// Start is the character offset where the match begins in the source
+	// text
+	Start int `json:"start"`
+	// End is the character offset where the match ends in the source text
+	End int `json:"end"`
+	// Line is the line the match begins on starting at 1
+	Line int `json:"line"`
+	// Column is the character the match begins at on its line starting at 1
+	Column int `json:"column"`
+	// start and end are the byte offsets of the match in the source text
+	// used to slice and edit it
+	start int
+	end   int
+}
+
+// ByStart is a sorting mechanism for sorting match results by position
+type ByStart []MatchResult
+
+func (m ByStart) Len() int           { return len(m) }
+func (m ByStart) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m ByStart) Less(i, j int) bool { return m[i].start < m[j].start }
+
+// FlattenMatches pulls the matches out of each chunk, converting their
+// indices into absolute character offsets within the source text, and orders
+// them by where they start.
+func FlattenMatches(text string, chunks rules.Chunks) []MatchResult {
+	result := []MatchResult{}
+
+	for _, chunk := range chunks {
+		for _, match := range chunk.Matches {
+			// Match indices count runes so they need to be turned into
+			// byte indices to slice the Data
+			first, last := chunk.SpanOf(match)
+			first, last = ByteIndex(chunk.Data, first), ByteIndex(chunk.Data, last)
+
+			// Sentence wide matches don't carry the text so fill it in
+			matched := match.Match
+			if matched == "" {
+				matched = chunk.Data[first:last]
+			}
+
+			suggestions := match.Suggestions
+			if suggestions == nil {
+				suggestions = []string{}
+			}
+
+			result = append(result, MatchResult{
+				Match:       matched,
+				Label:       match.Label,
+				Message:     match.Message,
+				Severity:    match.Severity,
+				Suggestions: suggestions,
+				Source:      chunk.Source,
+				start:       chunk.SourceOffset(first),
+				end:         chunk.SourceEnd(last),
+			})
+		}
+	}
+
+	sort.Stable(ByStart(result))
+
+	// The results are in order so the lines and characters can be counted
+	// in one pass. The end is counted on from the start since matches can
+	// overlap.
+	lines := NewLineCounter(text)
+	for i := range result {
+		r := &result[i]
+		r.Line, r.Column = lines.LineCol(r.start)
+		r.Start = lines.Char(r.start)
+		r.End = r.Start
+		if r.start < r.end && r.end <= len(text) {
+			r.End += utf8.RuneCountInString(text[r.start:r.end])
+		}
+	}
+
+	return result
+}
+
+// CountMatches adds up the overall score and the number of matches found for
+// each processor type.
+func CountMatches(chunks rules.Chunks) (int, map[string]int) {
+	var score int
+
+	matches := make(map[string]int)
+	for _, info := range rules.RegisteredProcessors() {
+		matches[info.Label] = 0
+	}
+
+	for _, chunk := range chunks {
+		for _, match := range chunk.Matches {
+			matches[match.Label] += 1
+		}
+
+		score += chunk.Score
+	}
+
+	return score, matches
+}
+
+// GetReadTime builds a human readable estimate of how long it takes to read
+// the given number of words.
+func GetReadTime(words int) string {
 	var buffer bytes.Buffer
 
-	readTime := float64(appSummary["words"]) / float64(AvgReadingSpeed)
+	readTime := float64(words) / float64(AvgReadingSpeed)
 	vals := strings.Split(strconv.FormatFloat(readTime, 'f', 2, 64), ".")
 	beforeDecimal, _ := strconv.Atoi(vals[0])
 	afterDecimal, _ := strconv.ParseFloat(vals[1], 64)
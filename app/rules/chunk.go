@@ -0,0 +1,163 @@
+package rules
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Match represents an actual matched result after processing
+type Match struct {
+	// Match is the actual word / phrase that matches
+	Match string
+	// Label is the type of processor
+	Label string
+	// Indices are the start and end points of the match counted in runes
+	// within the chunk's Data
+	Indices []int
+	// Message is the message from the processor
+	Message string
+	// Severity is how important the match is in the profile being used
+	Severity string
+	// Suggestions are concrete replacements for the match
+	Suggestions []string
+}
+
+// NewMatch is a convenience function to build a new Match instance
+func NewMatch(match string, label string, indices []int, msg string) *Match {
+	return &Match{
+		Match:    match,
+		Label:    label,
+		Indices:  indices,
+		Message:  msg,
+		Severity: DefaultSeverity,
+	}
+}
+
+// Tooltip builds the text shown when hovering over the match in the frontend
+func (m *Match) Tooltip() string {
+	if len(m.Suggestions) == 0 {
+		return m.Message
+	}
+
+	return m.Message + " Try: " + FormatSuggestions(m.Suggestions)
+}
+
+// FormatSuggestions lists the suggestions in a readable way
+func FormatSuggestions(suggestions []string) string {
+	var quoted []string
+
+	for _, s := range suggestions {
+		quoted = append(quoted, "'"+s+"'")
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// Chunk is a piece of data created from the Chunker
+type Chunk struct {
+	// Index refers to the order of the Chunk
+	Index int
+	// Data is the text being stored
+	Data string
+	// Offset is where the Data starts within the source document
+	Offset int
+	// Positions maps each byte of Data to its offset in the source document
+	// for chunkers which strip markup. It is nil when Data is a plain slice
+	// of the text starting at Offset.
+	Positions []int
+	// Source describes where the chunk came from in the source document such
+	// as the HTML element holding it
+	Source string
+	// HTML is the Data marked up with matches for the frontend
+	HTML string
+	// FirstWord saves the first word of the text for analysis
+	FirstWord string
+	// IsNewParagraph marks when new paragraph delimiters are needed
+	IsNewParagraph bool
+	// Messages store the helpful messages returned from processors
+	Matches []*Match
+	// Score refers to the overall score of this Chunk
+	Score int
+}
+
+// NewChunk is a convenience function to build a new Chunk instance
+func NewChunk(idx int, data string) *Chunk {
+	var matches []*Match
+
+	return &Chunk{
+		Index:          idx,
+		Data:           data,
+		Offset:         0,
+		Source:         "",
+		HTML:           "",
+		FirstWord:      "",
+		IsNewParagraph: false,
+		Matches:        matches,
+		Score:          0,
+	}
+}
+
+// SpanOf gives the rune span of a match within Data. Matches without indices
+// cover the whole chunk and indices outside of Data are clamped to it.
+func (c *Chunk) SpanOf(m *Match) (int, int) {
+	length := utf8.RuneCountInString(c.Data)
+	if len(m.Indices) != 2 {
+		return 0, length
+	}
+
+	first, last := m.Indices[0], m.Indices[1]
+	if first < 0 {
+		first = 0
+	}
+	if last > length {
+		last = length
+	}
+	if first > last {
+		first = last
+	}
+
+	return first, last
+}
+
+// SourceOffset converts a byte index in Data to its offset in the source
+// document
+func (c *Chunk) SourceOffset(i int) int {
+	if c.Positions == nil {
+		return c.Offset + i
+	}
+
+	if i >= len(c.Positions) {
+		return c.SourceEnd(i)
+	}
+
+	return c.Positions[i]
+}
+
+// SourceEnd converts a byte index in Data marking the end of a span to its offset
+// in the source document. The end follows the last character of the span so
+// any markup stripped after it isn't included.
+func (c *Chunk) SourceEnd(i int) int {
+	if c.Positions == nil {
+		return c.Offset + i
+	}
+
+	if i <= 0 || len(c.Positions) == 0 {
+		return c.Offset
+	}
+
+	if i > len(c.Positions) {
+		i = len(c.Positions)
+	}
+
+	return c.Positions[i-1] + 1
+}
+
+// Chunks is a simple way to reference data that has been split by a chunker
+type Chunks []*Chunk
+
+// ByChunk is a sorting mechanism for sorting a slice of chunks
+type ByChunk []*Chunk
+
+func (c ByChunk) Len() int           { return len(c) }
+func (c ByChunk) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c ByChunk) Less(i, j int) bool { return c[i].Index < c[j].Index }
@@ -0,0 +1,58 @@
+package rules
+
+import "testing"
+
+func TestSpanOf(t *testing.T) {
+	chunk := NewChunk(0, "naïve café")
+
+	tests := []struct {
+		indices     []int
+		first, last int
+	}{
+		{[]int{6, 10}, 6, 10},
+		{nil, 0, 10},
+		{[]int{-2, 3}, 0, 3},
+		{[]int{8, 40}, 8, 10},
+		{[]int{12, 14}, 10, 10},
+	}
+
+	for _, test := range tests {
+		first, last := chunk.SpanOf(&Match{Indices: test.indices})
+		if first != test.first || last != test.last {
+			t.Errorf("SpanOf(%v) = %d, %d, want %d, %d", test.indices, first, last, test.first, test.last)
+		}
+	}
+}
+
+func TestSourceOffset(t *testing.T) {
+	plain := NewChunk(0, "héllo")
+	plain.Offset = 10
+
+	mapped := NewChunk(0, "héllo")
+	mapped.Positions = []int{2, 3, 4, 5, 6, 9}
+
+	tests := []struct {
+		chunk     *Chunk
+		i         int
+		start     int
+		end       int
+		condition string
+	}{
+		{plain, 0, 10, 10, "plain start"},
+		{plain, 3, 13, 13, "plain after multi-byte"},
+		{mapped, 1, 3, 3, "mapped multi-byte start"},
+		{mapped, 3, 5, 5, "mapped after multi-byte"},
+		{mapped, 5, 9, 7, "mapped across stripped markup"},
+		{mapped, 6, 10, 10, "mapped end"},
+		{mapped, 9, 10, 10, "mapped past the end"},
+	}
+
+	for _, test := range tests {
+		if got := test.chunk.SourceOffset(test.i); got != test.start {
+			t.Errorf("%s: SourceOffset(%d) = %d, want %d", test.condition, test.i, got, test.start)
+		}
+		if got := test.chunk.SourceEnd(test.i); got != test.end {
+			t.Errorf("%s: SourceEnd(%d) = %d, want %d", test.condition, test.i, got, test.end)
+		}
+	}
+}
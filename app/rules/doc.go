@@ -0,0 +1,21 @@
+// Package rules holds what a processor needs to plug into write-better: the
+// chunks of text it looks at, the matches it adds to them, the settings a
+// profile gives it and the registry it adds itself to. A processor in another
+// package registers itself in an init function and is built in by importing
+// that package from main.
+//
+// There are two kinds of offsets used when tracking where a match is:
+//
+//   - Match.Indices are rune offsets within the chunk's Data. Processors work
+//     on runes so a multi-byte character never gets split and highlighting
+//     lines up with the frontend, which has one node per rune.
+//   - The results given back by the application have Start and End byte
+//     offsets into the source document as submitted, before any markup was
+//     stripped. They can be used to slice the original text and are what
+//     edits are applied with. The line and column give the same position for
+//     people with the column counted in characters.
+//
+// Chunk.SpanOf gives the rune span of a match within its chunk and
+// Chunk.SourceOffset and SourceEnd turn byte offsets in Data into source
+// document offsets.
+package rules
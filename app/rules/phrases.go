@@ -0,0 +1,190 @@
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ListEntry is a single phrase to look for from a phrase list
+type ListEntry struct {
+	// Phrase is the word or phrase to look for
+	Phrase string
+	// Replacements are the suggested phrases to use instead
+	Replacements []string
+	// Message replaces the list message for this phrase
+	Message string
+	// Pattern is the compiled expression used to find the phrase without
+	// its word boundaries, which FindAll checks
+	Pattern *regexp.Regexp
+	// wordStart and wordEnd are set when the phrase starts or ends with a
+	// word character and so needs a word boundary there
+	wordStart bool
+	wordEnd   bool
+}
+
+// NewListEntry is a convenience function to build a ListEntry which matches
+// the phrase case-insensitively on word boundaries.
+func NewListEntry(phrase string, replacements []string, msg string) *ListEntry {
+	words := strings.Fields(phrase)
+	phrase = strings.Join(words, " ")
+
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+
+	// Only use word boundaries on the edges that are word characters since
+	// phrases like "C++" would never match otherwise.
+	first, _ := utf8.DecodeRuneInString(phrase)
+	last, _ := utf8.DecodeLastRuneInString(phrase)
+
+	return &ListEntry{
+		Phrase:       phrase,
+		Replacements: replacements,
+		Message:      msg,
+		Pattern:      regexp.MustCompile(`(?i)` + strings.Join(words, `\s+`)),
+		wordStart:    isWordRune(first),
+		wordEnd:      isWordRune(last),
+	}
+}
+
+// FindAll finds the byte offsets of every place the phrase is used in the
+// text. The word boundaries are checked by hand since \b in Go's regular
+// expressions only knows ASCII letters and "café" would never match.
+func (e *ListEntry) FindAll(text string) [][]int {
+	var found [][]int
+
+	for pos := 0; pos < len(text); {
+		loc := e.Pattern.FindStringIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+
+		start, end := pos+loc[0], pos+loc[1]
+		if end > start && e.onBoundaries(text, start, end) {
+			found = append(found, []int{start, end})
+			pos = end
+			continue
+		}
+
+		// Try again from the next character since a match can start
+		// inside one that wasn't on a boundary
+		_, size := utf8.DecodeRuneInString(text[start:])
+		pos = start + size
+	}
+
+	return found
+}
+
+// onBoundaries checks that the text between start and end isn't part of a
+// longer word on either side
+func (e *ListEntry) onBoundaries(text string, start int, end int) bool {
+	if e.wordStart && start > 0 {
+		if before, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(before) {
+			return false
+		}
+	}
+
+	if e.wordEnd && end < len(text) {
+		if after, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(after) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LoadPhraseList reads a phrase list file. Each line holds a phrase with
+// optional replacements and a message separated by pipes:
+//
+//	utilize | use | Plain words are easier to read.
+//	leverage | use, take advantage of
+//	Github | GitHub
+//
+// Blank lines and lines starting with # are ignored.
+func LoadPhraseList(path string) ([]*ListEntry, error) {
+	var entries []*ListEntry
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		entry, err := ParsePhraseLine(s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, s.Err()
+}
+
+// ParsePhrases parses a list of phrases written the same way as the lines of
+// a phrase list file
+func ParsePhrases(lines []string) ([]*ListEntry, error) {
+	var entries []*ListEntry
+
+	for _, line := range lines {
+		entry, err := ParsePhraseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// ParsePhraseLine parses a single line of a phrase list. Blank lines and
+// comments give a nil entry.
+func ParsePhraseLine(line string) (*ListEntry, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(line, "|", 3)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	if parts[0] == "" {
+		return nil, fmt.Errorf("missing phrase in %q", line)
+	}
+
+	var replacements []string
+	var msg string
+
+	if len(parts) > 1 {
+		for _, r := range strings.Split(parts[1], ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				replacements = append(replacements, r)
+			}
+		}
+	}
+
+	if len(parts) > 2 {
+		msg = parts[2]
+	}
+
+	return NewListEntry(parts[0], replacements, msg), nil
+}
+
+// isWordRune checks if a rune is part of a word for word boundaries
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_'
+}
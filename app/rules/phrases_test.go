@@ -0,0 +1,65 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePhraseLine(t *testing.T) {
+	tests := []struct {
+		line         string
+		phrase       string
+		replacements []string
+		message      string
+	}{
+		{"utilize | use | Plain words are easier to read.", "utilize", []string{"use"}, "Plain words are easier to read."},
+		{"  leverage |use,  take advantage of ", "leverage", []string{"use", "take advantage of"}, ""},
+		{"in  order   to", "in order to", nil, ""},
+		{"Github | GitHub | Use the name | with a pipe", "Github", []string{"GitHub"}, "Use the name | with a pipe"},
+	}
+
+	for _, test := range tests {
+		entry, err := ParsePhraseLine(test.line)
+		if err != nil {
+			t.Errorf("ParsePhraseLine(%q) gave an error: %s", test.line, err)
+			continue
+		}
+
+		if entry.Phrase != test.phrase || !reflect.DeepEqual(entry.Replacements, test.replacements) || entry.Message != test.message {
+			t.Errorf("ParsePhraseLine(%q) = %q, %q, %q, want %q, %q, %q", test.line,
+				entry.Phrase, entry.Replacements, entry.Message, test.phrase, test.replacements, test.message)
+		}
+	}
+
+	for _, line := range []string{"", "   ", "# a comment"} {
+		if entry, err := ParsePhraseLine(line); entry != nil || err != nil {
+			t.Errorf("ParsePhraseLine(%q) = %v, %v, want nothing", line, entry, err)
+		}
+	}
+
+	if _, err := ParsePhraseLine(" | use"); err == nil {
+		t.Error("ParsePhraseLine gave no error for a missing phrase")
+	}
+}
+
+func TestListEntryFindAll(t *testing.T) {
+	tests := []struct {
+		phrase string
+		text   string
+		want   [][]int
+	}{
+		{"café", "A café, then Café au lait.", [][]int{{2, 7}, {14, 19}}},
+		{"café", "cafés and décafé", nil},
+		{"naïve", "naïve naïve", [][]int{{0, 6}, {7, 13}}},
+		{"in order to", "in  order\tto win", [][]int{{0, 12}}},
+		{"C++", "I like C++.", [][]int{{7, 10}}},
+		{"a a", "ba a a", [][]int{{3, 6}}},
+		{"it", "Italy, it's it", [][]int{{7, 9}, {12, 14}}},
+	}
+
+	for _, test := range tests {
+		if got := NewListEntry(test.phrase, nil, "").FindAll(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("FindAll(%q) in %q = %v, want %v", test.phrase, test.text, got, test.want)
+		}
+	}
+}
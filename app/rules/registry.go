@@ -0,0 +1,110 @@
+package rules
+
+import (
+	"fmt"
+)
+
+// Processor is an interface which handles processing of a Chunk.
+type Processor interface {
+	Process(*Chunk) *Chunk
+}
+
+// ParagraphProcessor is an interface which handles processing of a paragraph
+// given as the chunks for each of its sentences in order. Matches are added
+// to the chunks they belong to.
+type ParagraphProcessor interface {
+	ProcessParagraph(Chunks)
+}
+
+// DocumentProcessor is an interface which handles processing of the whole
+// document given as every chunk in order. Matches are added to the chunks
+// they belong to.
+type DocumentProcessor interface {
+	ProcessDocument(Chunks)
+}
+
+// ProcessorInfo describes a processor so that it can be configured and shown
+// to users without needing to know about the processor itself.
+type ProcessorInfo struct {
+	// Label is the unique key used for matches, config rules and styling
+	Label string
+	// Name is the title shown when describing the processor
+	Name string
+	// Legend is the text shown next to the number of matches in the results
+	Legend string
+	// Description explains what the processor looks for
+	Description string
+	// Message is the default message given with each match
+	Message string
+	// Messages are the other messages the processor gives keyed by the name
+	// a rule uses to replace them
+	Messages map[string]string
+	// Category groups similar processors together
+	Category string
+	// Color is the "r, g, b" value used to highlight matches
+	Color string
+	// Thresholds are the names of the thresholds the processor reads from
+	// its rule mapped to the smallest value each can be set to
+	Thresholds map[string]int
+	// Validate checks settings of a rule which depend on each other, such as
+	// one threshold needing to be above another. It may be nil.
+	Validate func(Rule) error
+	// SafeFix marks processors whose suggestions can replace a match without
+	// reading the words around it so they are applied when fixing by default
+	SafeFix bool
+	// New builds the processor using the rule settings from a profile
+	New func(Rule) Processor
+	// NewParagraph builds a processor which looks at whole paragraphs for
+	// processors which don't set New
+	NewParagraph func(Rule) ParagraphProcessor
+	// NewDocument builds a processor which looks at the whole document for
+	// processors which don't set New or NewParagraph
+	NewDocument func(Rule) DocumentProcessor
+}
+
+// registry stores the registered processors in the order they were added
+var registry []*ProcessorInfo
+
+// RegisterProcessor adds a processor to the registry making it available to
+// every profile. Processors register themselves in an init function so a new
+// rule only needs its own file or a package imported by main. It panics if the
+// label is already taken or the processor can't be built.
+func RegisterProcessor(info ProcessorInfo) {
+	builders := 0
+	if info.New != nil {
+		builders++
+	}
+	if info.NewParagraph != nil {
+		builders++
+	}
+	if info.NewDocument != nil {
+		builders++
+	}
+
+	if info.Label == "" || builders != 1 {
+		panic("RegisterProcessor: processor needs a label and one of New, NewParagraph or NewDocument")
+	}
+
+	if _, ok := LookupProcessor(info.Label); ok {
+		panic(fmt.Sprintf("RegisterProcessor: label %q registered twice", info.Label))
+	}
+
+	registry = append(registry, &info)
+}
+
+// LookupProcessor finds a registered processor by its label
+func LookupProcessor(label string) (*ProcessorInfo, bool) {
+	for _, info := range registry {
+		if info.Label == label {
+			return info, true
+		}
+	}
+
+	return nil, false
+}
+
+// RegisteredProcessors lists every registered processor in the order they
+// were added, which is also the order they are run in.
+func RegisteredProcessors() []*ProcessorInfo {
+	return registry
+}
@@ -0,0 +1,68 @@
+package rules
+
+// DefaultSeverity is the severity given to a rule without one set
+const DefaultSeverity = "warning"
+
+// Rule holds the settings for a single processor in a profile
+type Rule struct {
+	// Enabled turns the processor on or off, defaulting to on
+	Enabled *bool `toml:"enabled"`
+	// Severity marks how important matches from the processor are
+	Severity string `toml:"severity"`
+	// Message replaces the default message given with each match
+	Message string `toml:"message"`
+	// MessageSet records that the profile set Message rather than it being
+	// filled in with the processor's default, so processors with more than
+	// one message know to give Message for every match
+	MessageSet bool `toml:"-"`
+	// Messages replace the other messages of processors which give more than
+	// one kind of message, keyed by name
+	Messages map[string]string `toml:"messages"`
+	// Thresholds are processor specific limits such as sentence lengths
+	Thresholds map[string]int `toml:"thresholds"`
+	// Phrases replace the built in phrases for processors which look for a
+	// list of phrases. Each is written like a line of a phrase list file.
+	Phrases []string `toml:"phrases"`
+	// AddPhrases are looked for along with the built in phrases
+	AddPhrases []string `toml:"add_phrases"`
+}
+
+// IsEnabled checks if the processor should be run
+func (r Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// Entries gets the phrase list entries for the rule. The given entries are
+// used when the rule doesn't replace them with its own phrases and any added
+// phrases come first so they win over the built in ones.
+func (r Rule) Entries(def []*ListEntry) []*ListEntry {
+	// The phrases are checked when the config is loaded
+	entries, _ := ParsePhrases(r.AddPhrases)
+
+	if len(r.Phrases) == 0 {
+		return append(entries, def...)
+	}
+
+	replaced, _ := ParsePhrases(r.Phrases)
+
+	return append(entries, replaced...)
+}
+
+// Threshold gets a named threshold falling back to a default value
+func (r Rule) Threshold(name string, def int) int {
+	if val, ok := r.Thresholds[name]; ok {
+		return val
+	}
+
+	return def
+}
+
+// NamedMessage gets one of the other messages of a processor by name falling
+// back to a default message
+func (r Rule) NamedMessage(name string, def string) string {
+	if msg, ok := r.Messages[name]; ok {
+		return msg
+	}
+
+	return def
+}
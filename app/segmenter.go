@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultLanguage is the language used to split sentences when a profile
+// doesn't set one
+const DefaultLanguage = "en"
+
+// SentenceClosers are the characters which can follow the end of a sentence
+// and still belong to it such as closing quotes and brackets
+const SentenceClosers = "\"')]}”’»"
+
+// Segmenter finds where sentences end in a line of text. A full stop is only
+// treated as the end of a sentence when it isn't part of a number, a known
+// abbreviation or an initial.
+type Segmenter struct {
+	// Language is the name the segmenter is registered under
+	Language string
+	// Abbreviations are the lower case words, without their final full stop,
+	// which never end a sentence such as "dr" or "e.g"
+	Abbreviations map[string]bool
+}
+
+// segmenters stores the registered segmenters keyed by language
+var segmenters = make(map[string]*Segmenter)
+
+// RegisterLanguage adds the abbreviations to the segmenter for a language,
+// creating it if the language hasn't been registered before
+func RegisterLanguage(language string, abbreviations []string) {
+	s, ok := segmenters[language]
+	if !ok {
+		s = &Segmenter{Language: language, Abbreviations: make(map[string]bool)}
+		segmenters[language] = s
+	}
+
+	for _, abbr := range abbreviations {
+		abbr = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(abbr)), ".")
+		if abbr != "" {
+			s.Abbreviations[abbr] = true
+		}
+	}
+}
+
+// LookupSegmenter finds the segmenter for a language with an empty language
+// meaning the default
+func LookupSegmenter(language string) (*Segmenter, error) {
+	if language == "" {
+		language = DefaultLanguage
+	}
+
+	s, ok := segmenters[language]
+	if !ok {
+		return nil, fmt.Errorf("unknown language %q", language)
+	}
+
+	return s, nil
+}
+
+// DefaultSegmenter gives the segmenter for the default language
+func DefaultSegmenter() *Segmenter {
+	return segmenters[DefaultLanguage]
+}
+
+func init() {
+	RegisterLanguage("en", []string{
+		"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "mt", "ft",
+		"gen", "col", "lt", "sgt", "capt", "rev", "hon", "gov", "sen", "rep",
+		"e.g", "i.e", "cf", "vs", "etc", "al", "approx", "dept", "est", "fig",
+		"vol", "pp", "inc", "ltd", "co", "corp", "ph.d", "a.m", "p.m",
+		"u.s", "u.k", "u.n", "jan", "feb", "apr", "jun", "jul", "aug", "sep",
+		"sept", "oct", "nov", "dec", "mon", "tue", "thu", "fri",
+	})
+
+	RegisterLanguage("de", []string{
+		"dr", "prof", "hr", "fr", "nr", "str", "abs", "tel", "ca", "bzw",
+		"usw", "vgl", "evtl", "ggf", "sog", "inkl", "zzgl", "bspw", "etc",
+		"jh", "mio", "mrd", "z.b", "d.h", "u.a", "o.ä", "s.o", "s.u",
+	})
+}
+
+// Boundaries finds the byte offsets in the text where each sentence ends. The
+// end of the text is not included unless it ends with a sentence terminator.
+func (s *Segmenter) Boundaries(text string) map[int]bool {
+	ends := make(map[int]bool)
+
+	runes := []rune(text)
+
+	// offsets holds the byte offset of each rune along with the end
+	offsets := make([]int, 0, len(runes)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	for i := 0; i < len(runes); i++ {
+		if !IsEndOfSentence(runes[i]) {
+			continue
+		}
+
+		// Take in the whole run of terminators such as "?!" or "..."
+		end := i
+		for end < len(runes) && IsEndOfSentence(runes[end]) {
+			end++
+		}
+
+		// Closing quotes and brackets belong to the sentence they end
+		next := end
+		for next < len(runes) && strings.ContainsRune(SentenceClosers, runes[next]) {
+			next++
+		}
+
+		if s.isBoundary(runes, i, end, next) {
+			ends[offsets[next]] = true
+		}
+
+		i = next - 1
+	}
+
+	return ends
+}
+
+// isBoundary checks if the run of terminators from start to end ends a
+// sentence. The sentence would end at next, after any closing characters.
+func (s *Segmenter) isBoundary(runes []rune, start int, end int, next int) bool {
+	if next == len(runes) {
+		return true
+	}
+
+	// Sentences are followed by a space, otherwise this is something like a
+	// decimal, a domain name or "e.g.,"
+	if !IsSpace(runes[next]) {
+		return false
+	}
+
+	following := nextLetter(runes, next)
+	terminators := string(runes[start:end])
+
+	switch {
+	case terminators == ".":
+		word := wordBefore(runes, start)
+
+		if s.Abbreviations[strings.ToLower(word)] {
+			return false
+		}
+
+		// Initials such as the "J." in "J. Smith"
+		if len([]rune(word)) == 1 && unicode.IsUpper([]rune(word)[0]) {
+			return false
+		}
+
+		// A sentence doesn't start with a lower case letter so this is most
+		// likely an abbreviation we don't know about
+		return !unicode.IsLower(following)
+	case strings.Trim(terminators, ".…") == "":
+		// An ellipsis only ends a sentence when a new one clearly starts
+		return following == 0 || unicode.IsUpper(following)
+	}
+
+	return true
+}
+
+// wordBefore gets the word leading up to a full stop at index i including
+// any full stops within it such as "e.g" or "U.S"
+func wordBefore(runes []rune, i int) string {
+	start := i
+	for start > 0 && !IsSpace(runes[start-1]) {
+		start--
+	}
+
+	// Drop opening quotes and brackets
+	for start < i && !IsAlphaNumeric(runes[start]) {
+		start++
+	}
+
+	return string(runes[start:i])
+}
+
+// nextLetter finds the first letter or number after index i skipping over
+// spaces, quotes and brackets. It gives 0 when there isn't one.
+func nextLetter(runes []rune, i int) rune {
+	for ; i < len(runes); i++ {
+		if IsAlphaNumeric(runes[i]) {
+			return runes[i]
+		}
+	}
+
+	return 0
+}
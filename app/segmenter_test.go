@@ -0,0 +1,70 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// splitSentences cuts the text at each boundary the segmenter finds
+func splitSentences(s *Segmenter, text string) []string {
+	var ends []int
+	for end := range s.Boundaries(text) {
+		ends = append(ends, end)
+	}
+	sort.Ints(ends)
+
+	var sentences []string
+	start := 0
+	for _, end := range ends {
+		sentences = append(sentences, strings.TrimSpace(text[start:end]))
+		start = end
+	}
+
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+
+	return sentences
+}
+
+func TestBoundaries(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"One. Two! Three? Four", []string{"One.", "Two!", "Three?", "Four"}},
+		{"Ask Dr. Smith. She knows.", []string{"Ask Dr. Smith.", "She knows."}},
+		{"Bring fruit, e.g. apples. Then go.", []string{"Bring fruit, e.g. apples.", "Then go."}},
+		{"Use tools (e.g., hammers).", []string{"Use tools (e.g., hammers)."}},
+		{"It costs 3.50 today. Pay now.", []string{"It costs 3.50 today.", "Pay now."}},
+		{"Visit example.com today.", []string{"Visit example.com today."}},
+		{"Wait... what happened? We left... Then it rained.", []string{"Wait... what happened?", "We left...", "Then it rained."}},
+		{"He waited… and waited.", []string{"He waited… and waited."}},
+		{`She said "Stop." Then she left.`, []string{`She said "Stop."`, "Then she left."}},
+		{"It was late (very late.) We slept.", []string{"It was late (very late.)", "We slept."}},
+		{"He said “Go.” We went.", []string{"He said “Go.”", "We went."}},
+		{"Written by J. R. Tolkien. It is long.", []string{"Written by J. R. Tolkien.", "It is long."}},
+		{"Really?! Yes.", []string{"Really?!", "Yes."}},
+		{"Ends with an unknown abbr. and carries on.", []string{"Ends with an unknown abbr. and carries on."}},
+	}
+
+	for _, test := range tests {
+		if got := splitSentences(DefaultSegmenter(), test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q split into %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestBoundariesLanguage(t *testing.T) {
+	s, err := LookupSegmenter("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Das gilt z.B. hier.", "Dann nicht."}
+	if got := splitSentences(s, "Das gilt z.B. hier. Dann nicht."); !reflect.DeepEqual(got, want) {
+		t.Errorf("split into %q, want %q", got, want)
+	}
+}
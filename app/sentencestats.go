@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"write-better/rules"
+)
+
+// Limits for judging sentence length variety
+const (
+	// LowVarietyMinimum is the fewest sentences needed to judge variety
+	LowVarietyMinimum = 5
+	// LowVarietyRatio is the standard deviation as a share of the mean
+	// sentence length below which the lengths are too alike
+	LowVarietyRatio = 0.25
+)
+
+// HistogramBuckets are the upper limits in words of each sentence length
+// bucket with the last bucket holding anything longer
+var HistogramBuckets = []int{5, 10, 15, 20, 25, 30, 40}
+
+// HistogramBucket is the number of sentences within a range of lengths
+type HistogramBucket struct {
+	// Label describes the range of lengths such as "6-10"
+	Label string `json:"label"`
+	// Min is the shortest length in words in the bucket
+	Min int `json:"min"`
+	// Max is the longest length in words in the bucket with 0 meaning there
+	// is no limit
+	Max int `json:"max"`
+	// Count is the number of sentences in the bucket
+	Count int `json:"count"`
+	// Percent is the count as a share of the largest bucket for charting
+	Percent int `json:"percent"`
+}
+
+// SentenceStats describes how long the sentences of a text are in words
+type SentenceStats struct {
+	// Count is the number of sentences with words in them
+	Count int `json:"count"`
+	// Mean is the average sentence length
+	Mean float64 `json:"mean"`
+	// Median is the middle sentence length
+	Median float64 `json:"median"`
+	// StdDev is the standard deviation of the sentence lengths
+	StdDev float64 `json:"stdDev"`
+	// Shortest is the length of the shortest sentence
+	Shortest int `json:"shortest"`
+	// Longest is the length of the longest sentence
+	Longest int `json:"longest"`
+	// Histogram is the number of sentences in each range of lengths
+	Histogram []HistogramBucket `json:"histogram"`
+	// LowVariety marks text where the sentences are all about the same
+	// length giving it a monotonous rhythm
+	LowVariety bool `json:"lowVariety"`
+}
+
+// GetSentenceStats computes the sentence length statistics from the chunked
+// text. Sentences are measured in words.
+func GetSentenceStats(chunks rules.Chunks) SentenceStats {
+	var result SentenceStats
+	var lengths []int
+
+	for _, chunk := range chunks {
+		if words := len(Words(chunk.Data)); words > 0 {
+			lengths = append(lengths, words)
+		}
+	}
+
+	result.Histogram = buildHistogram(lengths)
+
+	if len(lengths) == 0 {
+		return result
+	}
+
+	sort.Ints(lengths)
+
+	total := 0
+	for _, length := range lengths {
+		total += length
+	}
+
+	mean := float64(total) / float64(len(lengths))
+
+	variance := 0.0
+	for _, length := range lengths {
+		variance += math.Pow(float64(length)-mean, 2)
+	}
+	stdDev := math.Sqrt(variance / float64(len(lengths)))
+
+	middle := len(lengths) / 2
+	median := float64(lengths[middle])
+	if len(lengths)%2 == 0 {
+		median = float64(lengths[middle-1]+lengths[middle]) / 2
+	}
+
+	result.Count = len(lengths)
+	result.Mean = round(mean)
+	result.Median = median
+	result.StdDev = round(stdDev)
+	result.Shortest = lengths[0]
+	result.Longest = lengths[len(lengths)-1]
+	result.LowVariety = len(lengths) >= LowVarietyMinimum && stdDev < mean*LowVarietyRatio
+
+	return result
+}
+
+// buildHistogram counts the sentence lengths into the HistogramBuckets
+func buildHistogram(lengths []int) []HistogramBucket {
+	var buckets []HistogramBucket
+
+	min := 1
+	for _, max := range HistogramBuckets {
+		buckets = append(buckets, HistogramBucket{Label: fmt.Sprintf("%d-%d", min, max), Min: min, Max: max})
+		min = max + 1
+	}
+	buckets = append(buckets, HistogramBucket{Label: fmt.Sprintf("%d+", min), Min: min})
+
+	for _, length := range lengths {
+		for i := range buckets {
+			if buckets[i].Max == 0 || length <= buckets[i].Max {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+
+	largest := 0
+	for _, bucket := range buckets {
+		if bucket.Count > largest {
+			largest = bucket.Count
+		}
+	}
+
+	if largest > 0 {
+		for i := range buckets {
+			buckets[i].Percent = buckets[i].Count * 100 / largest
+		}
+	}
+
+	return buckets
+}
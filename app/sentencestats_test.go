@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"write-better/rules"
+)
+
+// sentenceChunks builds a chunk for each sentence length in words
+func sentenceChunks(lengths ...int) rules.Chunks {
+	var chunks rules.Chunks
+	for i, length := range lengths {
+		chunks = append(chunks, rules.NewChunk(i, strings.Repeat("word ", length)))
+	}
+
+	return chunks
+}
+
+func TestSentenceStats(t *testing.T) {
+	chunks := append(sentenceChunks(8, 2, 30, 4, 6), rules.NewChunk(5, " -- "))
+
+	got := GetSentenceStats(chunks)
+	if got.Count != 5 || got.Mean != 10 || got.Median != 6 || got.StdDev != 10.2 || got.Shortest != 2 || got.Longest != 30 {
+		t.Errorf("stats are %+v", got)
+	}
+	if got.LowVariety {
+		t.Error("varied sentences were marked as low variety")
+	}
+
+	counts := map[string]int{"1-5": 2, "6-10": 2, "26-30": 1}
+	for _, bucket := range got.Histogram {
+		if bucket.Count != counts[bucket.Label] {
+			t.Errorf("bucket %s has %d sentences, want %d", bucket.Label, bucket.Count, counts[bucket.Label])
+		}
+	}
+	if last := got.Histogram[len(got.Histogram)-1]; last.Label != "41+" || last.Max != 0 {
+		t.Errorf("last bucket is %+v, want 41+ without a limit", last)
+	}
+	if got.Histogram[0].Percent != 100 || got.Histogram[5].Percent != 50 {
+		t.Errorf("percents are %d and %d, want 100 and 50", got.Histogram[0].Percent, got.Histogram[5].Percent)
+	}
+}
+
+func TestSentenceStatsLowVariety(t *testing.T) {
+	tests := []struct {
+		lengths []int
+		want    bool
+	}{
+		{[]int{10, 11, 10, 12, 11}, true},
+		{[]int{10, 11, 10, 12}, false},
+		{[]int{5, 20, 10, 12, 30}, false},
+	}
+
+	for _, test := range tests {
+		if got := GetSentenceStats(sentenceChunks(test.lengths...)).LowVariety; got != test.want {
+			t.Errorf("lengths %v gave low variety %v, want %v", test.lengths, got, test.want)
+		}
+	}
+
+	if got := GetSentenceStats(nil); got.Count != 0 || len(got.Histogram) != len(HistogramBuckets)+1 {
+		t.Errorf("no sentences gave %+v", got)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"write-better/rules"
+)
+
+// SessionLifetime is how long an analysis session is kept around
+const SessionLifetime = time.Hour
+
+// Session holds everything for a single submitted document so that multiple
+// users can run analyses at the same time without seeing each other's work.
+type Session struct {
+	// ID is the unique identifier used in the URL
+	ID string
+	// Text is the text that has been submitted
+	Text string
+	// Format is the format of the text such as plain text or Markdown
+	Format string
+	// Profile is the name of the style profile to process the text with
+	Profile string
+	// Result is the processed chunks of the text
+	Result rules.Chunks
+	// Summary is the overall data summary of the text
+	Summary Summary
+	// Created marks when the session was started
+	Created time.Time
+	// processed makes sure the text is only analyzed once
+	processed sync.Once
+	// err is the error from analyzing the text
+	err error
+}
+
+// SessionStore keeps track of the active sessions in the application
+type SessionStore struct {
+	sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewSessionStore is a convenience function to build a new SessionStore instance
+func NewSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string]*Session)}
+}
+
+// New starts a session for the given text, format and profile and stores it.
+// Expired sessions are cleaned out at the same time so the store doesn't grow
+// forever.
+func (s *SessionStore) New(text string, format string, profile string) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &Session{
+		ID:      id,
+		Text:    text,
+		Format:  format,
+		Profile: profile,
+		Created: time.Now(),
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for key, old := range s.sessions {
+		if time.Since(old.Created) > SessionLifetime {
+			delete(s.sessions, key)
+		}
+	}
+
+	s.sessions[id] = session
+
+	return session, nil
+}
+
+// Get looks up a session by its ID
+func (s *SessionStore) Get(id string) (*Session, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok || time.Since(session.Created) > SessionLifetime {
+		return nil, false
+	}
+
+	return session, true
+}
+
+// Process analyzes the text of a session the first time it is called and
+// stores the results. Later calls give back the error from that first run
+// without analyzing the text again.
+func (s *SessionStore) Process(session *Session, analyze func(*Session) (rules.Chunks, Summary, error)) error {
+	session.processed.Do(func() {
+		result, summary, err := analyze(session)
+
+		s.Lock()
+		defer s.Unlock()
+
+		session.Result = result
+		session.Summary = summary
+		session.err = err
+	})
+
+	s.Lock()
+	defer s.Unlock()
+
+	return session.err
+}
+
+// Results gets the processing results for a session. They are copied out
+// while the store is locked so they aren't read while being saved.
+func (s *SessionStore) Results(id string) (rules.Chunks, Summary, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok || time.Since(session.Created) > SessionLifetime || session.Summary == nil {
+		return nil, nil, false
+	}
+
+	return session.Result, session.Summary, true
+}
+
+// newSessionID builds a random hex string to identify a session
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"write-better/rules"
+)
+
+func TestSessionProcessesOnce(t *testing.T) {
+	store := NewSessionStore()
+
+	session, err := store.New("Some text.", "text", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := store.Results(session.ID); ok {
+		t.Error("Results gave results before the session was processed")
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	runs := 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			store.Process(session, func(s *Session) (rules.Chunks, Summary, error) {
+				mutex.Lock()
+				runs++
+				mutex.Unlock()
+
+				return rules.Chunks{rules.NewChunk(0, s.Text)}, Summary{"words": 2}, nil
+			})
+
+			store.Results(session.ID)
+		}()
+	}
+
+	wg.Wait()
+
+	if runs != 1 {
+		t.Errorf("the session was processed %d times, want once", runs)
+	}
+
+	chunks, summary, ok := store.Results(session.ID)
+	if !ok || len(chunks) != 1 || summary["words"] != 2 {
+		t.Errorf("Results() = %v, %v, %v", chunks, summary, ok)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "bytes"
+
+// SourceText builds up the text extracted from a source document such as
+// Markdown or HTML while keeping track of where each byte came from. This lets
+// chunkers strip markup but still report matches at their original position.
+type SourceText struct {
+	buffer bytes.Buffer
+	// Positions holds the source offset of each byte that has been written
+	Positions []int
+}
+
+// Write adds text copied from the source starting at the given offset
+func (t *SourceText) Write(s string, offset int) {
+	t.buffer.WriteString(s)
+	for i := 0; i < len(s); i++ {
+		t.Positions = append(t.Positions, offset+i)
+	}
+}
+
+// WriteAt adds text that doesn't appear in the source as-is, such as a
+// separator or a decoded entity, with every byte placed at the given offset
+func (t *SourceText) WriteAt(s string, offset int) {
+	t.buffer.WriteString(s)
+	for i := 0; i < len(s); i++ {
+		t.Positions = append(t.Positions, offset)
+	}
+}
+
+// WriteSpan adds text standing in for a span of the source, such as a decoded
+// entity, so the text covers the whole span from start to end
+func (t *SourceText) WriteSpan(s string, start int, end int) {
+	t.WriteAt(s, start)
+	if len(s) > 0 {
+		t.Positions[len(t.Positions)-1] = end - 1
+	}
+}
+
+// Len is the number of bytes written so far
+func (t *SourceText) Len() int {
+	return t.buffer.Len()
+}
+
+// String gives the text written so far
+func (t *SourceText) String() string {
+	return t.buffer.String()
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"write-better/rules"
+)
+
+// DefaultDictionary is the bundled dictionary used to check spelling. It is
+// found from the working directory or next to the executable.
+const DefaultDictionary = "dictionaries/en_US.dic"
+
+// DefaultSuggestions is the number of suggestions given for a misspelling
+const DefaultSuggestions = 3
+
+// MaxSuggestionLookups is the most misspellings in a document which are
+// looked up for suggestions. Later misspellings are still flagged but
+// without suggestions so a document full of them stays quick.
+const MaxSuggestionLookups = 500
+
+var (
+	// dictionaryOnce makes sure the bundled dictionary is only loaded once
+	dictionaryOnce sync.Once
+	// dictionary is the dictionary used by the spelling processor
+	dictionary *Dictionary
+)
+
+// SpellingDictionary gets the dictionary used to check spelling. The bundled
+// DefaultDictionary is loaded the first time it's needed unless another
+// dictionary was set with SetSpellingDictionary. It is nil when no
+// dictionary could be loaded.
+func SpellingDictionary() *Dictionary {
+	dictionaryOnce.Do(func() {
+		if dictionary != nil {
+			return
+		}
+
+		d, err := LoadDictionary(FindDictionary(DefaultDictionary))
+		if err != nil {
+			// Spelling isn't checked rather than flagging every word
+			log.Println("SpellingDictionary: spelling is turned off:", err)
+			return
+		}
+
+		dictionary = d
+	})
+
+	return dictionary
+}
+
+// FindDictionary finds a relative dictionary path by trying each of the
+// directories dirs, the working directory and then the directory of the
+// executable and its parent. The path is returned unchanged when it can't be
+// found so loading it gives a useful error.
+func FindDictionary(path string, dirs ...string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	dirs = append(dirs, ".")
+	if exe, err := executableDir(); err == nil {
+		dirs = append(dirs, exe, filepath.Dir(exe))
+	}
+
+	for _, dir := range dirs {
+		candidate := filepath.Join(dir, path)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+
+	return path
+}
+
+// executableDir gets the directory holding the running executable
+func executableDir() (string, error) {
+	path, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return "", err
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	return filepath.Dir(path), nil
+}
+
+// SetSpellingDictionary replaces the dictionary used to check spelling. It
+// should be set before any text is processed.
+func SetSpellingDictionary(d *Dictionary) {
+	dictionary = d
+}
+
+// SpellingConfig holds the settings for the dictionary used to check spelling
+type SpellingConfig struct {
+	// Dictionary is a dictionary file relative to the config file which
+	// replaces the bundled one
+	Dictionary string `toml:"dictionary"`
+	// Words are custom word list files relative to the config file with a
+	// word on each line such as product names and jargon
+	Words []string `toml:"words"`
+}
+
+// LoadSpellingConfig loads the configured dictionary along with the custom
+// word lists to be set with SetSpellingDictionary. It is nil when the config
+// doesn't change the dictionary. Relative files are found from the directory
+// dir. The bundled dictionary is looked for there first and then with
+// FindDictionary.
+func LoadSpellingConfig(spelling SpellingConfig, dir string) (*Dictionary, error) {
+	if spelling.Dictionary == "" && len(spelling.Words) == 0 {
+		return nil, nil
+	}
+
+	paths := []string{FindDictionary(DefaultDictionary, dir)}
+	if spelling.Dictionary != "" {
+		paths[0] = spelling.Dictionary
+		if !filepath.IsAbs(paths[0]) {
+			paths[0] = filepath.Join(dir, paths[0])
+		}
+	}
+
+	for _, file := range spelling.Words {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+
+		paths = append(paths, file)
+	}
+
+	return LoadDictionary(paths...)
+}
+
+var (
+	// inlineCode finds code written between backticks in plain text
+	inlineCode = regexp.MustCompile("`[^`]*`")
+	// spellingToken finds the runs of text between spaces
+	spellingToken = regexp.MustCompile(`\S+`)
+)
+
+// tokenPunctuation is trimmed from the edges of a token before it is checked
+const tokenPunctuation = "\"'([{<>}]).,;:!?…*_"
+
+// SpellingProcessor processes words which aren't in the dictionary
+type SpellingProcessor struct {
+	// Message is the message given with each match where %s is replaced with
+	// the misspelled word
+	Message string
+	// Dictionaries are the dictionaries a word can be found in to be spelled
+	// correctly
+	Dictionaries []*Dictionary
+	// Suggestions is the most suggestions given for each misspelling
+	Suggestions int
+	// lookups remembers the suggestions for the misspellings in a document
+	lookups *suggestionLookups
+}
+
+// suggestionLookups holds the suggestions looked up for the misspellings in
+// a document. Chunks are processed at the same time so it is locked.
+type suggestionLookups struct {
+	sync.Mutex
+	words map[string][]string
+}
+
+// newSuggestionLookups is a convenience function to build an empty
+// suggestionLookups
+func newSuggestionLookups() *suggestionLookups {
+	return &suggestionLookups{words: make(map[string][]string)}
+}
+
+// UseSpellingProcessor is a convenience variable for referencing a
+// SpellingProcessor. The dictionary is left out so it is only loaded when a
+// processor is built.
+var UseSpellingProcessor = SpellingProcessor{
+	Message:     "'%s' may be misspelled.",
+	Suggestions: DefaultSuggestions,
+}
+
+func init() {
+	rules.RegisterProcessor(rules.ProcessorInfo{
+		Label:       "spelling",
+		Name:        "Spelling",
+		Legend:      "Misspelled Words",
+		Description: "Misspelled words distract readers and undermine an otherwise careful piece of writing. Words which aren't in the dictionary are flagged along with the closest words that are. Add product names and jargon to a custom dictionary.",
+		Message:     UseSpellingProcessor.Message,
+		Category:    "correctness",
+		Color:       "230, 60, 90",
+		Thresholds:  map[string]int{"suggestions": 1},
+		New: func(r rules.Rule) rules.Processor {
+			// Without a dictionary every word would be flagged
+			d := SpellingDictionary()
+			if d == nil {
+				return SpellingProcessor{Message: r.Message}
+			}
+
+			// The rule's phrases are extra words to accept for the profile
+			custom := NewDictionary()
+			for _, entry := range r.Entries(nil) {
+				custom.Add(entry.Phrase)
+			}
+
+			return SpellingProcessor{
+				Message:      r.Message,
+				Dictionaries: []*Dictionary{d, custom},
+				Suggestions:  r.Threshold("suggestions", UseSpellingProcessor.Suggestions),
+				lookups:      newSuggestionLookups(),
+			}
+		},
+	})
+}
+
+// Process handles the processing for misspelled word matches. Code, URLs and
+// email addresses are skipped along with words containing numbers or
+// capitals after the first letter such as "iPhone" and "HTTP". Capitalized
+// words after the start of a sentence are taken to be names and skipped too.
+// Nothing is flagged without a dictionary.
+func (p SpellingProcessor) Process(c *rules.Chunk) *rules.Chunk {
+	if len(p.Dictionaries) == 0 {
+		return c
+	}
+
+	normalized := NormalizeForMatching(c.Data)
+	code := inlineCode.FindAllStringIndex(normalized, -1)
+	first := true
+
+	for _, token := range spellingToken.FindAllStringIndex(normalized, -1) {
+		if overlapsAny(code, token) {
+			first = false
+			continue
+		}
+
+		text := normalized[token[0]:token[1]]
+		start := token[0] + len(text) - len(strings.TrimLeft(text, tokenPunctuation))
+		end := token[0] + len(strings.TrimRight(text, tokenPunctuation))
+		if start >= end {
+			continue
+		}
+
+		word := normalized[start:end]
+		atStart := first
+		first = false
+
+		if !isSpellingWord(word) {
+			continue
+		}
+
+		// Each part of a hyphenated word is checked on its own
+		offset := start
+		for i, part := range strings.Split(word, "-") {
+			if i > 0 {
+				atStart = false
+			}
+
+			if p.isMisspelled(part, atStart) {
+				p.addMatch(c, []int{offset, offset + len(part)}, normalized)
+			}
+
+			offset += len(part) + 1
+		}
+	}
+
+	return c
+}
+
+// isSpellingWord checks if a token looks like a word worth checking rather
+// than code, a number, a URL or an email address
+func isSpellingWord(word string) bool {
+	for _, r := range word {
+		if !IsAlpha(r) && r != '\'' && r != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isMisspelled checks if a single word is missing from every dictionary
+func (p SpellingProcessor) isMisspelled(word string, atStart bool) bool {
+	word = strings.Trim(word, "'")
+
+	runes := []rune(word)
+	if len(runes) < 2 {
+		return false
+	}
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) && (i > 0 || !atStart) {
+			return false
+		}
+	}
+
+	if p.isKnown(word) {
+		return false
+	}
+
+	// Possessives are fine when the word itself is
+	if strings.HasSuffix(strings.ToLower(word), "'s") && p.isKnown(word[:len(word)-2]) {
+		return false
+	}
+
+	return true
+}
+
+// isKnown checks if any of the dictionaries has the word
+func (p SpellingProcessor) isKnown(word string) bool {
+	for _, d := range p.Dictionaries {
+		if d.Contains(word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addMatch adds a match for the misspelled word between the byte offsets of
+// the normalized text with suggestions from the dictionaries
+func (p SpellingProcessor) addMatch(c *rules.Chunk, found []int, normalized string) {
+	// Apostrophes around the word aren't part of the misspelling
+	word := normalized[found[0]:found[1]]
+	found[0] += len(word) - len(strings.TrimLeft(word, "'"))
+	found[1] -= len(word) - len(strings.TrimRight(word, "'"))
+
+	indices := RuneIndices(normalized, found)
+	match := RuneSlice(c.Data, indices[0], indices[1])
+
+	m := rules.NewMatch(match, "spelling", indices, FormatMessage(p.Message, match))
+
+	for _, s := range p.suggest(match) {
+		m.Suggestions = append(m.Suggestions, MatchCase(match, s))
+	}
+
+	c.Matches = append(c.Matches, m)
+	c.Score += 1
+}
+
+// suggest gives the suggestions for a misspelled word. Each word is only
+// looked up once for a document and nothing is suggested once
+// MaxSuggestionLookups words have been looked up.
+func (p SpellingProcessor) suggest(word string) []string {
+	if p.lookups == nil {
+		return Suggest(word, p.Suggestions, p.Dictionaries...)
+	}
+
+	key := strings.ToLower(word)
+
+	p.lookups.Lock()
+	defer p.lookups.Unlock()
+
+	if suggestions, ok := p.lookups.words[key]; ok {
+		return suggestions
+	}
+	if len(p.lookups.words) >= MaxSuggestionLookups {
+		return nil
+	}
+
+	suggestions := Suggest(word, p.Suggestions, p.Dictionaries...)
+	p.lookups.words[key] = suggestions
+
+	return suggestions
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"html"
 	"strings"
 )
 
@@ -47,17 +48,17 @@ func (nodes CharNodes) ToString() string {
 	return strings.Join(str, "")
 }
 
-// ToCharNodes converts a string to a list of CharNode references for processing
+// ToCharNodes converts a string to a list of CharNode references for
+// processing. There is one node for each UTF-8 character so node indices line
+// up with rune indices. Each char is escaped since the nodes become HTML.
 func ToCharNodes(s string) CharNodes {
 	var nodes CharNodes
 	var emptyStrList []string
 
-	chars := strings.Split(s, "")
-
-	for i := 0; i < len(chars); i++ {
+	for i, r := range []rune(s) {
 		nodes = append(nodes, &CharNode{
 			Index:  i,
-			Char:   chars[i],
+			Char:   html.EscapeString(string(r)),
 			Before: emptyStrList,
 			After:  emptyStrList,
 		})
@@ -78,7 +79,7 @@ func (c *CharNode) AddAfter(s string) {
 
 // OpenTag builds a <span> tag to go before the char (project specific)
 func OpenTag(procType string, msg string) string {
-	return fmt.Sprintf("<span data-placement=\"top\" data-toggle=\"tooltip\" title=\"%s\" class=\"match type-%s\">", msg, procType)
+	return fmt.Sprintf("<span data-placement=\"top\" data-toggle=\"tooltip\" title=\"%s\" class=\"match type-%s\">", html.EscapeString(msg), procType)
 }
 
 // CloseTag builds a <span> tag to go after the char (project specific)
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Suggester gives the suggested replacements for a matched word or phrase
+type Suggester func(match string) []string
+
+// wordySuggestions are the plainer replacements for common wordy phrases
+var wordySuggestions = map[string][]string{
+	"a large number of":         {"many"},
+	"a majority of":             {"most"},
+	"a number of":               {"some", "several"},
+	"additional":                {"more", "extra"},
+	"along the lines of":        {"like"},
+	"approximately":             {"about"},
+	"as a means of":             {"to"},
+	"assistance":                {"help"},
+	"at all times":              {"always"},
+	"at the present time":       {"now"},
+	"at this point in time":     {"now"},
+	"attempt":                   {"try"},
+	"by means of":               {"by"},
+	"commence":                  {"begin", "start"},
+	"demonstrate":               {"show"},
+	"despite the fact that":     {"although"},
+	"due to the fact that":      {"because"},
+	"each and every":            {"each", "every"},
+	"endeavor":                  {"try"},
+	"facilitate":                {"help"},
+	"first and foremost":        {"first"},
+	"for the purpose of":        {"to", "for"},
+	"for the reason that":       {"because"},
+	"has the ability to":        {"can"},
+	"in close proximity":        {"near"},
+	"in excess of":              {"more than"},
+	"in light of the fact that": {"because"},
+	"in order to":               {"to"},
+	"in regard to":              {"about"},
+	"in spite of the fact that": {"although"},
+	"in the event that":         {"if"},
+	"in the near future":        {"soon"},
+	"is able to":                {"can"},
+	"numerous":                  {"many"},
+	"obtain":                    {"get"},
+	"on a daily basis":          {"daily"},
+	"owing to the fact that":    {"because"},
+	"prior to":                  {"before"},
+	"purchase":                  {"buy"},
+	"subsequent to":             {"after"},
+	"sufficient":                {"enough"},
+	"terminate":                 {"end"},
+	"the majority of":           {"most"},
+	"until such time as":        {"until"},
+	"utilize":                   {"use"},
+	"whether or not":            {"whether"},
+	"with regard to":            {"about"},
+	"with respect to":           {"about"},
+}
+
+// SuggestFromTable builds a Suggester which looks up replacements in a table
+// keyed by the lowercase phrase. The suggestions follow the capitalization of
+// the match.
+func SuggestFromTable(table map[string][]string) Suggester {
+	return func(match string) []string {
+		var result []string
+
+		key := strings.ToLower(strings.Join(strings.Fields(match), " "))
+		for _, suggestion := range table[key] {
+			result = append(result, MatchCase(match, suggestion))
+		}
+
+		return result
+	}
+}
+
+// suggestSingleWord suggests keeping one copy of a repeated word
+func suggestSingleWord(match string) []string {
+	words := strings.Fields(match)
+	if len(words) == 0 {
+		return nil
+	}
+
+	return []string{words[0]}
+}
+
+// MatchCase capitalizes the suggestion when the match starts with a capital
+// letter so replacements at the start of a sentence still read correctly.
+func MatchCase(match string, suggestion string) string {
+	first, _ := utf8.DecodeRuneInString(match)
+	if !unicode.IsUpper(first) || suggestion == "" {
+		return suggestion
+	}
+
+	r, size := utf8.DecodeRuneInString(suggestion)
+
+	return string(unicode.ToUpper(r)) + suggestion[size:]
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"sync"
 	"text/template"
+
+	"write-better/rules"
 )
 
 // templateHandler allows us to load an HTML file and serve it. We parse the
@@ -16,7 +18,10 @@ type templateHandler struct {
 }
 
 func (t *templateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var data map[string]interface{}
+	data := map[string]interface{}{
+		"profiles":   appConfig.ProfileNames(),
+		"processors": rules.RegisteredProcessors(),
+	}
 	RenderTemplate(t, w, data)
 }
 
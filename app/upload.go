@@ -4,14 +4,12 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
-
-	unidecode "github.com/rainycape/unidecode"
 )
 
-// uploadHandler reads POST data from the file field and sets it in the app
+// uploadHandler reads POST data from the file field and starts a new session
 func uploaderHandler(w http.ResponseWriter, req *http.Request) {
 	// Use io.Reader type of req.FormFile to read the file and headers
-	file, _, err := req.FormFile("textFile")
+	file, header, err := req.FormFile("textFile")
 	if err != nil {
 		io.WriteString(w, err.Error())
 		return
@@ -24,8 +22,19 @@ func uploaderHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	appText = unidecode.Unidecode(string(data))
+	// Word processor documents need their text pulled out of the archive
+	text, err := ExtractDocument(header.Filename, data)
+	if err != nil {
+		io.WriteString(w, err.Error())
+		return
+	}
+
+	session, err := appSessions.New(text, DetectFormat(header.Filename), req.FormValue("profile"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	w.Header().Set("Location", "/process")
+	w.Header().Set("Location", "/process/"+session.ID)
 	w.WriteHeader(http.StatusTemporaryRedirect)
 }